@@ -5,104 +5,444 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/gofrs/flock"
 )
 
+const (
+	cacheFileName = "cache.json"
+	lockFileName  = "cache.lock"
+	fileExt       = ".zip"
+	valueExt      = ".json"
+
+	// DefaultMaxSize is the size limit of the cache when none is configured
+	DefaultMaxSize int64 = 500 << 20
+
+	// accessResolution avoids saving the cache on every hit only to record the access time
+	accessResolution = time.Minute
+
+	// inlineLimit is the size above which values are stored in their own file rather than in
+	// the cache file, so that saving the cache does not grow with the size of the cached values
+	inlineLimit = 4 << 10
+)
+
+// maxSize is the size limit above which the least recently used entries are evicted
+var maxSize = DefaultMaxSize
+
+// fileMu serializes access to the cache files between the caches of this process,
+// the file lock does the same between processes
+var fileMu sync.Mutex
+
+// CacheEntry keeps its value encoded, it is decoded into the type requested by Get
 type CacheEntry struct {
-	Key       string        `json:"key"`
-	Data      any           `json:"data"`
-	Timestamp time.Time     `json:"timestamp"`
-	TTL       time.Duration `json:"ttl"`
+	Key       string          `json:"key"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+	TTL       time.Duration   `json:"ttl"`
+	// AccessedAt is the time of the last hit, used to evict the least recently used entries
+	AccessedAt time.Time `json:"accessed_at"`
+	// FileSize is the size of the file of entries set with SetFileCache or stored in a value file
+	FileSize int64 `json:"file_size,omitempty"`
+	// ValueFile is set when the value is stored in its own file instead of Data
+	ValueFile bool `json:"value_file,omitempty"`
+}
+
+func (e *CacheEntry) expired() bool {
+	return time.Since(e.Timestamp) > e.TTL
+}
+
+func (e *CacheEntry) lastAccess() time.Time {
+	if e.AccessedAt.IsZero() {
+		return e.Timestamp
+	}
+	return e.AccessedAt
+}
+
+// size returns the disk usage of the entry
+func (e *CacheEntry) size() int64 {
+	return int64(len(e.Data)) + e.FileSize
+}
+
+// SetMaxSize sets the size limit of the cache, a size of zero or less disables the limit
+func SetMaxSize(size int64) {
+	maxSize = size
+}
+
+// MaxSize returns the size limit of the cache
+func MaxSize() int64 {
+	return maxSize
 }
 
 type Cache struct {
+	mu      sync.Mutex
 	entries map[string]*CacheEntry
 	dir     string
+	// loaded identifies the version of the cache file the entries were read from
+	loaded fileStamp
+}
+
+// fileStamp tells whether the cache file changed since it was read
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   *Cache
+)
+
+// Shared returns the cache of the process, loaded on first use. Its entries are read again
+// only when the cache file is changed by another process.
+func Shared() (*Cache, error) {
+	cacheDir, err := dir()
+	if err != nil {
+		return nil, err
+	}
+
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	if shared != nil && shared.dir == cacheDir {
+		return shared, nil
+	}
+	c, err := load(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	shared = c
+	return c, nil
 }
 
+// LoadCache reads the cache from disk, prefer Shared to reuse the cache of the process
 func LoadCache() (*Cache, error) {
+	cacheDir, err := dir()
+	if err != nil {
+		return nil, err
+	}
+	return load(cacheDir)
+}
+
+func dir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("error finding home directory: %w", err)
+		return "", fmt.Errorf("error finding home directory: %w", err)
 	}
 
 	cacheHome := os.Getenv("XDG_CACHE_HOME")
 	if cacheHome == "" {
 		cacheHome = filepath.Join(home, ".cache")
 	}
-	cacheDir := filepath.Join(cacheHome, "gh-ci")
+	return filepath.Join(cacheHome, "gh-ci"), nil
+}
 
+func load(cacheDir string) (*Cache, error) {
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return nil, err
 	}
 
 	c := &Cache{
-		entries: make(map[string]*CacheEntry),
-		dir:     cacheDir,
+		dir: cacheDir,
 	}
 
-	cacheFile := filepath.Join(cacheDir, "cache.json")
-	if data, err := os.ReadFile(cacheFile); err == nil {
-		if err := json.Unmarshal(data, &c.entries); err != nil {
-			return nil, fmt.Errorf("error loading cache: %w", err)
-		}
+	err := c.withLock(func() error {
+		c.removeTempFiles()
+		c.entries, c.loaded = c.read()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading cache: %w", err)
 	}
 
 	return c, nil
 }
 
-func (c *Cache) Get(key string) (any, bool) {
+// Get returns the value cached under key decoded as T. An entry that cannot be decoded as T,
+// for example one written by an older version, is reported as missing.
+func Get[T any](c *Cache, key string) (T, bool) {
+	var value T
+	data, found := c.get(key)
+	if !found {
+		return value, false
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		log.Printf("error decoding cache entry %s: %v", key, err)
+		return value, false
+	}
+	return value, true
+}
+
+// Set caches value under key for ttl
+func Set[T any](c *Cache, key string, value T, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error encoding cache entry %s: %w", key, err)
+	}
+	return c.set(key, data, ttl)
+}
+
+func (c *Cache) get(key string) (json.RawMessage, bool) {
 	hashedKey := c.hashKey(key)
-	entry, exists := c.entries[hashedKey]
+	entry, exists := c.lookup(hashedKey)
 
 	if !exists {
 		return nil, false
 	}
 
-	if time.Since(entry.Timestamp) > entry.TTL {
-		delete(c.entries, hashedKey)
-		if err := c.save(); err != nil {
-			fmt.Printf("error saving cache after TTL expiration: %v\n", err)
+	if entry.expired() {
+		err := c.update(func(entries map[string]*CacheEntry) {
+			if entry, ok := entries[hashedKey]; ok && entry.expired() {
+				c.remove(entries, hashedKey)
+			}
+		})
+		if err != nil {
+			log.Printf("error saving cache after TTL expiration: %v", err)
 		}
 		return nil, false
 	}
 
-	return entry.Data, true
+	data := entry.Data
+	if entry.ValueFile {
+		var err error
+		if data, err = os.ReadFile(c.valuePath(hashedKey)); err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("error reading cache entry %s: %v", key, err)
+			}
+			return nil, false
+		}
+	}
+
+	c.touch(hashedKey, entry)
+	return data, true
 }
 
-func (c *Cache) Set(key string, data any, ttl time.Duration) error {
+// lookup returns the entry of a hashed key, after reading the cache file again
+// if another process changed it
+func (c *Cache) lookup(hashedKey string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stamp := c.stamp(); stamp != c.loaded {
+		err := c.withLock(func() error {
+			c.entries, c.loaded = c.read()
+			return nil
+		})
+		if err != nil {
+			log.Printf("error reloading cache: %v", err)
+		}
+	}
+
+	entry, exists := c.entries[hashedKey]
+	return entry, exists
+}
+
+// touch records an access to the entry, at most once per accessResolution
+func (c *Cache) touch(hashedKey string, entry *CacheEntry) {
+	if time.Since(entry.lastAccess()) < accessResolution {
+		return
+	}
+	now := time.Now()
+	err := c.update(func(entries map[string]*CacheEntry) {
+		if entry, ok := entries[hashedKey]; ok {
+			entry.AccessedAt = now
+		}
+	})
+	if err != nil {
+		log.Printf("error saving cache access time: %v", err)
+	}
+}
+
+func (c *Cache) set(key string, data json.RawMessage, ttl time.Duration) error {
 	hashedKey := c.hashKey(key)
-	c.entries[hashedKey] = &CacheEntry{
-		Key:       key,
-		Data:      data,
-		Timestamp: time.Now(),
-		TTL:       ttl,
+	now := time.Now()
+	entry := &CacheEntry{
+		Key:        key,
+		Data:       data,
+		Timestamp:  now,
+		TTL:        ttl,
+		AccessedAt: now,
 	}
 
-	return c.save()
+	if len(data) > inlineLimit {
+		if err := writeFileAtomic(c.valuePath(hashedKey), data); err != nil {
+			return fmt.Errorf("error writing cache entry %s: %w", key, err)
+		}
+		entry.Data = nil
+		entry.FileSize = int64(len(data))
+		entry.ValueFile = true
+	}
+
+	return c.update(func(entries map[string]*CacheEntry) {
+		if previous, ok := entries[hashedKey]; ok && previous.ValueFile && !entry.ValueFile {
+			removeFile(c.valuePath(hashedKey))
+		}
+		entries[hashedKey] = entry
+	})
 }
 
 func (c *Cache) Delete(key string) error {
 	hashedKey := c.hashKey(key)
-	delete(c.entries, hashedKey)
-	return c.save()
+	return c.update(func(entries map[string]*CacheEntry) {
+		c.remove(entries, hashedKey)
+	})
+}
+
+// remove deletes an entry along with its files if any
+func (c *Cache) remove(entries map[string]*CacheEntry, hashedKey string) {
+	delete(entries, hashedKey)
+	removeFile(c.filePath(hashedKey))
+	removeFile(c.valuePath(hashedKey))
+}
+
+func removeFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("error removing cache file %s: %v", path, err)
+	}
+}
+
+func (c *Cache) filePath(hashedKey string) string {
+	return filepath.Join(c.dir, hashedKey+fileExt)
+}
+
+// valuePath returns the file of the values too large to be kept in the cache file
+func (c *Cache) valuePath(hashedKey string) string {
+	return filepath.Join(c.dir, hashedKey+valueExt)
+}
+
+// withLock runs fn while holding both the process and the cross-process cache locks
+func (c *Cache) withLock(fn func() error) error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	lock := flock.New(filepath.Join(c.dir, lockFileName))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("error locking cache: %w", err)
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			log.Printf("error unlocking cache: %v", err)
+		}
+	}()
+
+	return fn()
+}
+
+// update applies fn to the entries currently on disk and saves them,
+// so that entries written by other instances since this cache was loaded are kept.
+// Entries are evicted when the cache grows beyond its size limit.
+func (c *Cache) update(fn func(entries map[string]*CacheEntry)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.withLock(func() error {
+		entries, _ := c.read()
+		fn(entries)
+		c.evict(entries, maxSize)
+		if err := c.save(entries); err != nil {
+			return err
+		}
+		c.entries = entries
+		c.loaded = c.stamp()
+		return nil
+	})
+}
+
+// read returns the entries saved on disk along with the stamp of the file they were read from.
+// A corrupted cache file is moved aside and an empty cache is returned instead.
+func (c *Cache) read() (map[string]*CacheEntry, fileStamp) {
+	entries := make(map[string]*CacheEntry)
+	cacheFile := filepath.Join(c.dir, cacheFileName)
+	stamp := c.stamp()
+
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("error reading cache: %v", err)
+		}
+		return entries, stamp
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("corrupted cache file %s, starting from an empty cache: %v", cacheFile, err)
+		if err := os.Rename(cacheFile, cacheFile+".corrupt"); err != nil {
+			log.Printf("error moving corrupted cache file: %v", err)
+		}
+		return make(map[string]*CacheEntry), c.stamp()
+	}
+
+	return entries, stamp
 }
 
-func (c *Cache) Clear() error {
-	c.entries = make(map[string]*CacheEntry)
-	return c.save()
+// stamp returns the stamp of the cache file, the zero stamp when it does not exist
+func (c *Cache) stamp() fileStamp {
+	info, err := os.Stat(filepath.Join(c.dir, cacheFileName))
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
 }
 
-func (c *Cache) save() error {
-	cacheFile := filepath.Join(c.dir, "cache.json")
-	data, err := json.MarshalIndent(c.entries, "", "  ")
+func (c *Cache) save(entries map[string]*CacheEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(cacheFile, data, 0644)
+	return writeFileAtomic(filepath.Join(c.dir, cacheFileName), data)
+}
+
+// removeTempFiles removes the temporary cache files left over by a crashed write. The cache file
+// is written under the lock, the files of the entries are not: only the old ones are removed.
+func (c *Cache) removeTempFiles() {
+	matches, err := filepath.Glob(filepath.Join(c.dir, "*.tmp"))
+	if err != nil {
+		return
+	}
+	for _, match := range matches {
+		if !strings.HasPrefix(filepath.Base(match), cacheFileName+".") {
+			if info, err := os.Stat(match); err != nil || time.Since(info.ModTime()) < accessResolution {
+				continue
+			}
+		}
+		if err := os.Remove(match); err != nil && !os.IsNotExist(err) {
+			log.Printf("error removing temporary cache file %s: %v", match, err)
+		}
+	}
+}
+
+// writeFileAtomic writes data to a temporary file renamed over path,
+// so readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
 }
 
 func (c *Cache) hashKey(key string) string {
@@ -112,23 +452,30 @@ func (c *Cache) hashKey(key string) string {
 
 func (c *Cache) GetFileCache(key string) (string, bool) {
 	hashedKey := c.hashKey(key)
-	filePath := filepath.Join(c.dir, hashedKey+".zip")
+	filePath := c.filePath(hashedKey)
 
-	if entry, exists := c.entries[hashedKey]; exists {
-		if time.Since(entry.Timestamp) > entry.TTL {
-			delete(c.entries, hashedKey)
-			if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-				fmt.Printf("error removing expired cache file %s: %v\n", filePath, err)
-			}
-			if err := c.save(); err != nil {
-				fmt.Printf("error saving cache after file TTL expiration: %v\n", err)
+	entry, exists := c.lookup(hashedKey)
+	if !exists {
+		return "", false
+	}
+
+	if entry.expired() {
+		err := c.update(func(entries map[string]*CacheEntry) {
+			if entry, ok := entries[hashedKey]; ok && !entry.expired() {
+				// Refreshed by another instance in the meantime
+				return
 			}
-			return "", false
+			c.remove(entries, hashedKey)
+		})
+		if err != nil {
+			log.Printf("error saving cache after file TTL expiration: %v", err)
 		}
+		return "", false
+	}
 
-		if _, err := os.Stat(filePath); err == nil {
-			return filePath, true
-		}
+	if _, err := os.Stat(filePath); err == nil {
+		c.touch(hashedKey, entry)
+		return filePath, true
 	}
 
 	return "", false
@@ -136,20 +483,29 @@ func (c *Cache) GetFileCache(key string) (string, bool) {
 
 func (c *Cache) SetFileCache(key string, data []byte, ttl time.Duration) (string, error) {
 	hashedKey := c.hashKey(key)
-	filePath := filepath.Join(c.dir, hashedKey+".zip")
+	filePath := c.filePath(hashedKey)
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := writeFileAtomic(filePath, data); err != nil {
 		return "", err
 	}
 
-	c.entries[hashedKey] = &CacheEntry{
-		Key:       key,
-		Data:      filePath,
-		Timestamp: time.Now(),
-		TTL:       ttl,
+	encodedPath, err := json.Marshal(filePath)
+	if err != nil {
+		return "", err
 	}
-
-	if err := c.save(); err != nil {
+	now := time.Now()
+	entry := &CacheEntry{
+		Key:        key,
+		Data:       encodedPath,
+		Timestamp:  now,
+		TTL:        ttl,
+		AccessedAt: now,
+		FileSize:   int64(len(data)),
+	}
+	err = c.update(func(entries map[string]*CacheEntry) {
+		entries[hashedKey] = entry
+	})
+	if err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,162 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpaluszek/gh-ci/cache"
+	"github.com/cpaluszek/gh-ci/github"
+)
+
+func newCache(t *testing.T) *cache.Cache {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	c, err := cache.LoadCache()
+	if err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	return c
+}
+
+func reload(t *testing.T) *cache.Cache {
+	t.Helper()
+	c, err := cache.LoadCache()
+	if err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	return c
+}
+
+// roundTrip sets value in a cache and returns it as read by a freshly loaded one
+func roundTrip[T any](t *testing.T, c *cache.Cache, key string, value T) T {
+	t.Helper()
+	if err := cache.Set(c, key, value, time.Hour); err != nil {
+		t.Fatalf("Set %s: %v", key, err)
+	}
+	got, found := cache.Get[T](reload(t), key)
+	if !found {
+		t.Fatalf("Get %s: entry not found after reload", key)
+	}
+	return got
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := newCache(t)
+	date := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	steplogs := []github.Steplog{{
+		Number:   1,
+		Title:    "Run tests",
+		Status:   "failure",
+		Duration: "12s",
+		Logs: []github.LogEntry{
+			{Timestamp: "2024-05-01T12:30:00Z", Level: "error", Message: "FAIL"},
+		},
+		Collapsed: true,
+	}}
+	if got := roundTrip(t, c, "logs:owner/repo:1:1:test", steplogs); !reflect.DeepEqual(got, steplogs) {
+		t.Errorf("steplogs = %+v, want %+v", got, steplogs)
+	}
+
+	run := &github.WorkflowRun{
+		ID:         42,
+		WorkflowID: 7,
+		Name:       "CI",
+		RunAttempt: 2,
+		Status:     "completed",
+		Conclusion: "success",
+		CreatedAt:  date,
+		UpdatedAt:  date.Add(time.Minute),
+		Event:      "push",
+		HeadBranch: "main",
+		Actor:      github.User{Login: "octocat"},
+		Jobs: []*github.Job{
+			{ID: 3, RunID: 42, RunAttempt: 2, Name: "build", StartedAt: date, CompletedAt: date.Add(time.Minute)},
+		},
+	}
+	if got := roundTrip(t, c, "run:owner/repo:42", run); !reflect.DeepEqual(got, run) {
+		t.Errorf("run = %+v, want %+v", got, run)
+	}
+
+	// Large enough to be stored in its own file
+	snapshot := []*github.Repository{{
+		ID:            1,
+		Name:          "repo",
+		FullName:      "owner/repo",
+		DefaultBranch: "main",
+		UpdatedAt:     date,
+		Topics:        []string{strings.Repeat("topic", 2000)},
+		Workflows: []*github.Workflow{
+			{ID: 7, Name: "CI", Path: ".github/workflows/ci.yml", Runs: []*github.WorkflowRun{run}},
+		},
+		FetchedAt: date,
+	}}
+	if got := roundTrip(t, c, "snapshot:owner/repo:owner/repo||", snapshot); !reflect.DeepEqual(got, snapshot) {
+		t.Errorf("snapshot = %+v, want %+v", got, snapshot)
+	}
+
+	if info, err := os.Stat(filepath.Join(c.Dir(), "cache.json")); err != nil || info.Size() > 4<<10 {
+		t.Errorf("the snapshot was saved in the cache file: %v", err)
+	}
+
+	usages := reload(t).Usage()
+	if len(usages) != 1 || usages[0].Repository != "owner/repo" || usages[0].Entries != 3 {
+		t.Errorf("usage = %+v, want the 3 entries of owner/repo", usages)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := newCache(t)
+	if err := cache.Set(c, "expiring", "value", time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cache.Set(c, "kept", "value", time.Hour); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if _, found := cache.Get[string](reload(t), "expiring"); found {
+		t.Error("expired entry was returned")
+	}
+	if _, found := cache.Get[string](reload(t), "kept"); !found {
+		t.Error("entry within its TTL was not returned")
+	}
+
+	// The expired entry is removed from disk when read
+	usages := reload(t).Usage()
+	if len(usages) != 1 || usages[0].Entries != 1 {
+		t.Errorf("usage = %+v, want a single entry left", usages)
+	}
+}
+
+func TestCorruptedCacheFile(t *testing.T) {
+	c := newCache(t)
+	if err := cache.Set(c, "key", "value", time.Hour); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	cacheFile := filepath.Join(c.Dir(), "cache.json")
+	if err := os.WriteFile(cacheFile, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c = reload(t)
+	if _, found := cache.Get[string](c, "key"); found {
+		t.Error("entry was read from a corrupted cache file")
+	}
+	if data, err := os.ReadFile(cacheFile + ".corrupt"); err != nil || string(data) != "{not json" {
+		t.Errorf("corrupted file was not moved aside: %q, %v", data, err)
+	}
+
+	// The cache starts over from an empty file
+	if err := cache.Set(c, "key", "fresh", time.Hour); err != nil {
+		t.Fatalf("Set after recovery: %v", err)
+	}
+	if got, found := cache.Get[string](reload(t), "key"); !found || got != "fresh" {
+		t.Errorf("Get after recovery = %q, %v, want \"fresh\", true", got, found)
+	}
+}
@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Usage is the disk usage of the cache entries of a repository
+type Usage struct {
+	// Repository is 'owner/repo', empty for entries not tied to a repository
+	Repository string
+	Entries    int
+	Size       int64
+}
+
+// PruneResult reports what a Prune or Clear removed
+type PruneResult struct {
+	Entries int
+	Files   int
+	Freed   int64
+}
+
+// Repository returns the 'owner/repo' the entry belongs to. Keys of repository data are
+// formatted as "<kind>:<owner>/<repo>:...", other entries return an empty string.
+func (e *CacheEntry) Repository() string {
+	parts := strings.SplitN(e.Key, ":", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	owner, repo, ok := strings.Cut(parts[1], "/")
+	if !ok || owner == "" || repo == "" || strings.ContainsAny(repo, "/*?[") {
+		return ""
+	}
+	return parts[1]
+}
+
+// Dir returns the directory holding the cache files
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Usage returns the disk usage of the cache per repository, largest first
+func (c *Cache) Usage() []Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byRepository := make(map[string]*Usage)
+	for _, entry := range c.entries {
+		repo := entry.Repository()
+		usage, ok := byRepository[repo]
+		if !ok {
+			usage = &Usage{Repository: repo}
+			byRepository[repo] = usage
+		}
+		usage.Entries++
+		usage.Size += entry.size()
+	}
+
+	usages := make([]Usage, 0, len(byRepository))
+	for _, usage := range byRepository {
+		usages = append(usages, *usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Size != usages[j].Size {
+			return usages[i].Size > usages[j].Size
+		}
+		return usages[i].Repository < usages[j].Repository
+	})
+	return usages
+}
+
+// Clear removes every entry and file of the cache
+func (c *Cache) Clear() (PruneResult, error) {
+	return c.prune(func(entries map[string]*CacheEntry) {
+		for hashedKey := range entries {
+			c.remove(entries, hashedKey)
+		}
+	})
+}
+
+// Prune removes the expired entries, the files no entry refers to,
+// and the least recently used entries beyond the size limit
+func (c *Cache) Prune() (PruneResult, error) {
+	return c.prune(func(entries map[string]*CacheEntry) {
+		for hashedKey, entry := range entries {
+			if entry.expired() {
+				c.remove(entries, hashedKey)
+			}
+		}
+	})
+}
+
+func (c *Cache) prune(fn func(entries map[string]*CacheEntry)) (PruneResult, error) {
+	var result PruneResult
+	err := c.update(func(entries map[string]*CacheEntry) {
+		countBefore, sizeBefore := len(entries), totalSize(entries)
+		fn(entries)
+		// Evict now rather than in update, so that evicted entries are counted
+		c.evict(entries, maxSize)
+		result.Entries = countBefore - len(entries)
+		result.Freed = sizeBefore - totalSize(entries)
+
+		files, freed := c.removeOrphanFiles(entries)
+		result.Files = files
+		result.Freed += freed
+	})
+	return result, err
+}
+
+// evict removes the least recently used entries until the cache fits in limit,
+// the most recently used entry is always kept
+func (c *Cache) evict(entries map[string]*CacheEntry, limit int64) {
+	if limit <= 0 {
+		return
+	}
+	total := totalSize(entries)
+	if total <= limit {
+		return
+	}
+
+	keys := make([]string, 0, len(entries))
+	for hashedKey := range entries {
+		keys = append(keys, hashedKey)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].lastAccess().Before(entries[keys[j]].lastAccess())
+	})
+
+	for _, hashedKey := range keys[:len(keys)-1] {
+		if total <= limit {
+			break
+		}
+		total -= entries[hashedKey].size()
+		c.remove(entries, hashedKey)
+	}
+}
+
+// removeOrphanFiles removes the cache files no entry refers to, left over by older versions
+// or by entries removed from another process
+func (c *Cache) removeOrphanFiles(entries map[string]*CacheEntry) (int, int64) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*"+fileExt))
+	if err != nil {
+		return 0, 0
+	}
+	values, err := filepath.Glob(filepath.Join(c.dir, "*"+valueExt))
+	if err != nil {
+		return 0, 0
+	}
+
+	var count int
+	var freed int64
+	for _, match := range append(files, values...) {
+		name := filepath.Base(match)
+		if name == cacheFileName {
+			continue
+		}
+		hashedKey := strings.TrimSuffix(name, filepath.Ext(name))
+		if _, ok := entries[hashedKey]; ok {
+			continue
+		}
+		info, err := os.Stat(match)
+		// Recent files may belong to an entry being added by another process
+		if err != nil || time.Since(info.ModTime()) < accessResolution {
+			continue
+		}
+		if err := os.Remove(match); err != nil {
+			log.Printf("error removing cache file %s: %v", match, err)
+			continue
+		}
+		count++
+		freed += info.Size()
+	}
+	return count, freed
+}
+
+func totalSize(entries map[string]*CacheEntry) int64 {
+	var total int64
+	for _, entry := range entries {
+		total += entry.size()
+	}
+	return total
+}
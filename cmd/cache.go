@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/cpaluszek/gh-ci/cache"
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache <command>",
+		Short: "Manage the local cache",
+		Long: `Manage the cache of logs and API responses.
+
+The cache size is limited by the 'cache.max_size' setting of the config file, 500MB by default.
+The least recently used entries are evicted beyond it, a size of 0 disables the limit.
+
+These commands work even when the config file is invalid, with the default limit.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "info",
+		Short: "Show the disk usage of the cache per repository",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheInfo()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove every entry of the cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCachePrune((*cache.Cache).Clear)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "prune",
+		Short: "Remove expired entries and evict entries beyond the size limit",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCachePrune((*cache.Cache).Prune)
+		},
+	})
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newCacheCmd())
+}
+
+func runCacheInfo() error {
+	c, err := cache.Shared()
+	if err != nil {
+		return err
+	}
+
+	usages := c.Usage()
+	var total int64
+	for _, usage := range usages {
+		total += usage.Size
+	}
+
+	t := term.FromEnv()
+	limit := "none"
+	if cache.MaxSize() > 0 {
+		limit = formatSize(cache.MaxSize())
+	}
+	fmt.Fprintf(t.Out(), "Directory: %s\nSize: %s of %s\n\n", c.Dir(), formatSize(total), limit)
+
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+	tp := tableprinter.New(t.Out(), t.IsTerminalOutput(), width)
+	tp.AddHeader([]string{"REPOSITORY", "ENTRIES", "SIZE"})
+	for _, usage := range usages {
+		repo := usage.Repository
+		if repo == "" {
+			repo = "(other)"
+		}
+		tp.AddField(repo)
+		tp.AddField(fmt.Sprint(usage.Entries))
+		tp.AddField(formatSize(usage.Size))
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+func runCachePrune(prune func(*cache.Cache) (cache.PruneResult, error)) error {
+	c, err := cache.Shared()
+	if err != nil {
+		return err
+	}
+
+	result, err := prune(c)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d entries and %d orphan files, freed %s\n", result.Entries, result.Files, formatSize(result.Freed))
+	return nil
+}
+
+// configureCache applies the cache settings of the config file
+func configureCache() error {
+	cfg, err := config.LoadCacheConfig()
+	if err != nil {
+		return err
+	}
+	size, set, err := cfg.MaxSizeBytes()
+	if err != nil {
+		return err
+	}
+	if set {
+		cache.SetMaxSize(size)
+	}
+	return nil
+}
+
+// isCacheCmd reports whether cmd manages the cache, those commands must work with an invalid config
+func isCacheCmd(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Name() == "cache" && cmd.HasParent() && cmd.Parent() == cmd.Root() {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/jq"
+	"github.com/cli/go-gh/v2/pkg/template"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+// exportFlags holds the --json, --jq and --template flags of non-interactive commands
+type exportFlags struct {
+	fields    []string
+	available []string
+	jq        string
+	template  string
+}
+
+func addExportFlags(cmd *cobra.Command, available []string) *exportFlags {
+	f := &exportFlags{available: available}
+	cmd.Flags().StringSliceVar(&f.fields, "json", nil, "output JSON with the specified `fields`: "+strings.Join(available, ", "))
+	cmd.Flags().StringVarP(&f.jq, "jq", "q", "", "filter JSON output using a jq `expression`")
+	cmd.Flags().StringVarP(&f.template, "template", "t", "", "format JSON output using a Go template")
+	return f
+}
+
+func (f *exportFlags) enabled() bool {
+	return len(f.fields) > 0
+}
+
+func (f *exportFlags) validate() error {
+	if !f.enabled() {
+		if f.jq != "" {
+			return fmt.Errorf("cannot use `--jq` without specifying `--json`")
+		}
+		if f.template != "" {
+			return fmt.Errorf("cannot use `--template` without specifying `--json`")
+		}
+		return nil
+	}
+	if f.jq != "" && f.template != "" {
+		return fmt.Errorf("only one of `--jq` or `--template` may be used")
+	}
+	for _, field := range f.fields {
+		if !slices.Contains(f.available, field) {
+			return fmt.Errorf("unknown JSON field: %q\navailable fields:\n  %s", field, strings.Join(f.available, "\n  "))
+		}
+	}
+	return nil
+}
+
+// write outputs data restricted to the selected fields, through the jq expression or template if any.
+// data is either a single object or a list of objects.
+func (f *exportFlags) write(t term.Term, data any) error {
+	encoded, err := json.Marshal(f.selectFields(data))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case f.jq != "":
+		return jq.EvaluateFormatted(bytes.NewReader(encoded), t.Out(), f.jq, "  ", t.IsColorEnabled())
+	case f.template != "":
+		width, _, err := t.Size()
+		if err != nil {
+			width = 80
+		}
+		tmpl := template.New(t.Out(), width, t.IsColorEnabled())
+		if err := tmpl.Parse(f.template); err != nil {
+			return err
+		}
+		if err := tmpl.Execute(bytes.NewReader(encoded)); err != nil {
+			return err
+		}
+		return tmpl.Flush()
+	default:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, "", "  "); err != nil {
+			return err
+		}
+		indented.WriteString("\n")
+		_, err := io.Copy(t.Out(), &indented)
+		return err
+	}
+}
+
+func (f *exportFlags) selectFields(data any) any {
+	switch data := data.(type) {
+	case []map[string]any:
+		selected := make([]map[string]any, len(data))
+		for i, item := range data {
+			selected[i] = f.selectFields(item).(map[string]any)
+		}
+		return selected
+	case map[string]any:
+		selected := make(map[string]any, len(f.fields))
+		for _, field := range f.fields {
+			selected[field] = data[field]
+		}
+		return selected
+	}
+	return data
+}
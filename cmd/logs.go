@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/spf13/cobra"
+)
+
+var logFields = []string{
+	"job",
+	"step",
+	"stepNumber",
+	"stepStatus",
+	"timestamp",
+	"level",
+	"message",
+}
+
+type logsOptions struct {
+	step       string
+	failedOnly bool
+	grep       string
+	raw        bool
+	export     *exportFlags
+}
+
+func newLogsCmd() *cobra.Command {
+	opts := &logsOptions{}
+	cmd := &cobra.Command{
+		Use:   "logs <run-or-job-url>",
+		Short: "Print the logs of a run or job",
+		Long: `Print the logs of every job of a run, or of a single job.
+
+Each line is prefixed with its job and step separated by tabs, use --raw to print
+the log lines only.`,
+		Example: `  gh ci logs https://github.com/owner/repo/actions/runs/123 --failed-only
+  gh ci logs https://github.com/owner/repo/actions/runs/123/job/456 --step test --grep panic
+  gh ci logs https://github.com/owner/repo/actions/runs/123 --json step,message`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.raw && opts.export.enabled() {
+				return fmt.Errorf("only one of `--raw` or `--json` may be used")
+			}
+			return opts.export.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogs(opts, args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.step, "step", "s", "", "only print steps matching this name or number")
+	cmd.Flags().BoolVar(&opts.failedOnly, "failed-only", false, "only print the logs of failed steps")
+	cmd.Flags().StringVarP(&opts.grep, "grep", "g", "", "only print lines matching this regular `expression`")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "print log lines without job and step prefixes")
+	opts.export = addExportFlags(cmd, logFields)
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newLogsCmd())
+}
+
+// logLine is a log entry along with the job and step it belongs to
+type logLine struct {
+	Job   string
+	Step  github.Steplog
+	Entry github.LogEntry
+}
+
+func (l logLine) exportData() map[string]any {
+	return map[string]any{
+		"job":        l.Job,
+		"step":       l.Step.Title,
+		"stepNumber": l.Step.Number,
+		"stepStatus": l.Step.Status,
+		"timestamp":  l.Entry.Timestamp,
+		"level":      l.Entry.Level,
+		"message":    l.Entry.Message,
+	}
+}
+
+func runLogs(opts *logsOptions, rawURL string) error {
+	info, err := github.ParseGitHubURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	var grep *regexp.Regexp
+	if opts.grep != "" {
+		grep, err = regexp.Compile(opts.grep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep expression: %w", err)
+		}
+	}
+
+	client, err := github.NewClient()
+	if err != nil {
+		return err
+	}
+
+	// Keep the output pipe-friendly
+	log.SetOutput(io.Discard)
+
+	jobs, err := client.FetchJobs(info.User, info.Repo, info.RunID, info.Attempt, info.JobID)
+	if err != nil {
+		return err
+	}
+
+	var lines []logLine
+	for _, job := range jobs {
+		if opts.failedOnly && !github.IsFailureConclusion(job.Conclusion) {
+			continue
+		}
+
+		steps, err := client.GetLogs(info.User, info.Repo, info.RunID, info.LogAttempt(job), job.Name)
+		if err != nil {
+			return err
+		}
+
+		for _, step := range steps {
+			if opts.failedOnly && !github.IsFailureConclusion(step.Status) {
+				continue
+			}
+			if !matchStep(step, opts.step) {
+				continue
+			}
+			for _, entry := range step.Logs {
+				if grep != nil && !grep.MatchString(entry.Message) {
+					continue
+				}
+				lines = append(lines, logLine{Job: job.Name, Step: step, Entry: entry})
+			}
+		}
+	}
+
+	t := term.FromEnv()
+	if opts.export.enabled() {
+		data := make([]map[string]any, len(lines))
+		for i, line := range lines {
+			data[i] = line.exportData()
+		}
+		return opts.export.write(t, data)
+	}
+
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	out := t.Out()
+	for _, line := range lines {
+		message := line.Entry.Message
+		if t.IsColorEnabled() {
+			switch line.Entry.Level {
+			case "error":
+				message = errorStyle.Render(message)
+			case "warning":
+				message = warningStyle.Render(message)
+			}
+		}
+
+		if opts.raw {
+			if line.Entry.Timestamp != "" {
+				message = line.Entry.Timestamp + " " + message
+			}
+			fmt.Fprintln(out, message)
+			continue
+		}
+		fmt.Fprintf(out, "%s\t%s\t%s\n", line.Job, line.Step.Title, message)
+	}
+	return nil
+}
+
+// matchStep reports whether a step number equals the filter or its title contains it, ignoring case
+func matchStep(step github.Steplog, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	if number, err := strconv.Atoi(filter); err == nil {
+		return step.Number == number
+	}
+	return strings.Contains(strings.ToLower(step.Title), strings.ToLower(filter))
+}
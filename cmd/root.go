@@ -1,24 +1,153 @@
 package cmd
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
 	"github.com/cpaluszek/gh-ci/ui"
+	"github.com/cpaluszek/gh-ci/ui/keys"
+	"github.com/cpaluszek/gh-ci/ui/styles"
+	"github.com/spf13/cobra"
 )
 
+var repoFlag string
+var profileFlag string
+
+var rootCmd = &cobra.Command{
+	Use:   "ci [<run-or-job-url>]",
+	Short: "Monitor your GitHub Actions workflows and runs",
+	Long: `Monitor your GitHub Actions workflows and runs.
+
+Inside a git checkout, the workflows of the current repository are shown, filtered to
+the current branch. Elsewhere, the repositories of the config file are listed.
+
+With a run or job URL, that run is opened directly, with the logs of the job if any.`,
+	Example: `  gh ci
+  gh ci --repo owner/repo
+  gh ci https://github.com/owner/repo/actions/runs/123/job/456`,
+	Annotations: map[string]string{
+		cobra.CommandDisplayNameAnnotation: "gh ci",
+	},
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := configureCache(); err != nil && !isCacheCmd(cmd) {
+			return err
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTUI(args)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&repoFlag, "repo", "R", "", "select a single repository using the `owner/name` format")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "use the groups, theme and filters of a `profile` of the config")
+}
+
+// exitCodeError makes the process exit with a specific code without printing anything
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
 func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig returns the config restricted to the --repo flag or to the repository of the
+// current git checkout, along with that repository. Otherwise the config file is used as is,
+// or narrowed to the --profile flag.
+func loadConfig() (*config.Config, string, error) {
+	if repoFlag != "" {
+		cfg, err := config.LoadForRepository(repoFlag)
+		return cfg, repoFlag, err
+	}
+	if profileFlag != "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, "", err
+		}
+		if err := cfg.UseProfile(profileFlag); err != nil {
+			return nil, "", err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, "", fmt.Errorf("invalid profile '%s': %w", profileFlag, err)
+		}
+		return cfg, "", nil
+	}
+	if repo, ok := currentRepository(); ok {
+		cfg, err := config.LoadForRepository(repo)
+		return cfg, repo, err
+	}
 	cfg, err := config.Load()
+	return cfg, "", err
+}
+
+// loadTheme returns a builtin theme or one of the themes directory, the default one when name is empty
+func loadTheme(name string) (*styles.Theme, error) {
+	themesDir, err := config.ThemesDir()
 	if err != nil {
-		log.Fatal(err)
-		return
+		return nil, err
+	}
+	return styles.LoadTheme(name, themesDir)
+}
+
+func runTUI(args []string) (err error) {
+	var cfg *config.Config
+	var opts ui.Options
+	if len(args) == 1 {
+		cfg, opts, err = urlOptions(args[0])
+	} else {
+		var repo string
+		cfg, repo, err = loadConfig()
+		if errors.Is(err, config.ErrNoConfig) && profileFlag == "" && term.FromEnv().IsTerminalOutput() {
+			cfg, err = runSetup()
+		}
+		opts.Repository = repo
+		if repo != "" && repoFlag == "" {
+			opts.Branch = currentBranch()
+		}
+		// Configs narrowed to a single repository do not follow the config file
+		opts.WatchConfig = repo == ""
+		opts.Profile = profileFlag
+	}
+	if err != nil {
+		return err
+	}
+	if err := keys.Keys.Customize(cfg.Keys); err != nil {
+		return fmt.Errorf("invalid keys in config: %w", err)
+	}
+	opts.Theme, err = loadTheme(cfg.Theme)
+	if err != nil {
+		return err
 	}
 
 	// Redirect logs to a file
 	f, err := tea.LogToFile("debug.log", "debug")
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer func() {
 		closeErr := f.Close()
@@ -27,7 +156,7 @@ func Execute() {
 		}
 	}()
 
-	model := ui.NewModel(cfg)
+	model := ui.NewModel(cfg, opts)
 
 	p := tea.NewProgram(
 		model,
@@ -35,6 +164,58 @@ func Execute() {
 	)
 
 	if _, err := p.Run(); err != nil {
-		log.Fatal("Failed to run program:", err)
+		return fmt.Errorf("failed to run program: %w", err)
+	}
+	return nil
+}
+
+// urlOptions returns the config and options opening the run, and job if any, of a GitHub Actions URL
+func urlOptions(rawURL string) (*config.Config, ui.Options, error) {
+	info, err := github.ParseGitHubURL(rawURL)
+	if err != nil {
+		return nil, ui.Options{}, err
+	}
+
+	opts := ui.Options{Repository: info.User + "/" + info.Repo}
+	opts.RunID, err = strconv.ParseInt(info.RunID, 10, 64)
+	if err != nil {
+		return nil, ui.Options{}, fmt.Errorf("invalid run ID %s: %w", info.RunID, err)
+	}
+	if info.Attempt != "" {
+		opts.Attempt, err = strconv.Atoi(info.Attempt)
+		if err != nil {
+			return nil, ui.Options{}, fmt.Errorf("invalid attempt %s: %w", info.Attempt, err)
+		}
+	}
+	if info.JobID != "" {
+		opts.JobID, err = strconv.ParseInt(info.JobID, 10, 64)
+		if err != nil {
+			return nil, ui.Options{}, fmt.Errorf("invalid job ID %s: %w", info.JobID, err)
+		}
+	}
+
+	cfg, err := config.LoadForRepository(opts.Repository)
+	return cfg, opts, err
+}
+
+// currentRepository returns the 'owner/name' of the git checkout in the working directory
+func currentRepository() (string, bool) {
+	repo, err := repository.Current()
+	if err != nil {
+		return "", false
+	}
+	return repo.Owner + "/" + repo.Name, true
+}
+
+// currentBranch returns the branch checked out in the working directory, empty when detached
+func currentBranch() string {
+	out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return ""
 	}
+	return branch
 }
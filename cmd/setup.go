@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/ui/setup"
+)
+
+// runSetup lets the user pick the repositories to follow among those they can access,
+// then writes them to a new config file and loads it
+func runSetup() (*config.Config, error) {
+	client, err := github.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	p := tea.NewProgram(setup.NewModel(client), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run setup: %w", err)
+	}
+	m := final.(setup.Model)
+	if m.Err() != nil {
+		return nil, m.Err()
+	}
+	if m.Canceled {
+		return nil, fmt.Errorf("setup canceled, run gh ci again to pick the repositories to follow")
+	}
+
+	path, err := config.Create(m.Selected())
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Created %s, edit it to add groups, profiles or themes\n", path)
+	return config.Load()
+}
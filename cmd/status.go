@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/ui/utils"
+	"github.com/spf13/cobra"
+)
+
+var statusFields = []string{
+	"repository",
+	"workflow",
+	"id",
+	"title",
+	"status",
+	"conclusion",
+	"event",
+	"branch",
+	"commit",
+	"createdAt",
+	"updatedAt",
+	"url",
+	"error",
+}
+
+type statusOptions struct {
+	branch    string
+	workflows []string
+	export    *exportFlags
+}
+
+func newStatusCmd() *cobra.Command {
+	opts := &statusOptions{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the latest run of every workflow",
+		Long: `Show the latest run of every workflow of the selected repositories.
+
+Repositories are selected with --repo, the current git checkout or the config file.
+Repositories that cannot be fetched are listed as errors, and the filters of the config apply.
+Repositories that cannot be discovered and workflows whose runs cannot be fetched are reported
+on stderr. The command exits with a non-zero status when any of the listed workflows is failing
+or anything could not be fetched.`,
+		Example: `  gh ci status
+  gh ci status --branch main --workflow CI
+  gh ci status --json workflow,conclusion --jq '.[] | select(.conclusion == "failure")'`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.export.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "only consider runs of this branch")
+	cmd.Flags().StringSliceVarP(&opts.workflows, "workflow", "w", nil, "only show workflows with these names")
+	opts.export = addExportFlags(cmd, statusFields)
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newStatusCmd())
+}
+
+// statusConcurrency is the number of repositories fetched at the same time
+const statusConcurrency = 10
+
+// workflowStatus is the latest run of a workflow, or the error of a repository that could not be fetched
+type workflowStatus struct {
+	Repository string
+	Workflow   *github.Workflow
+	Run        *github.WorkflowRun
+	Error      error
+}
+
+func (s workflowStatus) workflowName() string {
+	if s.Workflow == nil {
+		return ""
+	}
+	return s.Workflow.Name
+}
+
+func (s workflowStatus) exportData() map[string]any {
+	if s.Error != nil {
+		return map[string]any{
+			"repository": s.Repository,
+			"status":     "error",
+			"error":      s.Error.Error(),
+		}
+	}
+	return map[string]any{
+		"repository": s.Repository,
+		"workflow":   s.Workflow.Name,
+		"id":         s.Run.ID,
+		"title":      s.Run.DisplayTitle,
+		"status":     s.Run.Status,
+		"conclusion": s.Run.Conclusion,
+		"event":      s.Run.Event,
+		"branch":     s.Run.HeadBranch,
+		"commit":     s.Run.HeadCommit.ID,
+		"createdAt":  s.Run.CreatedAt,
+		"updatedAt":  s.Run.UpdatedAt,
+		"url":        s.Run.URL,
+		"error":      nil,
+	}
+}
+
+func runStatus(opts *statusOptions) error {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := github.NewClient()
+	if err != nil {
+		return err
+	}
+
+	// Failures are reported on stderr below, without the timestamps and duplicates of the logger
+	log.SetOutput(io.Discard)
+
+	names, err := client.ResolveRepositories(cfg.Github)
+	incomplete := err != nil
+	if err != nil {
+		if !errors.Is(err, github.ErrIncompleteDiscovery) {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	statuses, failed := fetchStatuses(client, cfg.Github, names, opts)
+	incomplete = incomplete || failed
+
+	t := term.FromEnv()
+	if opts.export.enabled() {
+		data := make([]map[string]any, len(statuses))
+		for i, status := range statuses {
+			data[i] = status.exportData()
+		}
+		err = opts.export.write(t, data)
+	} else {
+		err = printStatuses(t, statuses)
+	}
+	if err != nil {
+		return err
+	}
+
+	if incomplete {
+		return &exitCodeError{code: 1}
+	}
+	for _, status := range statuses {
+		if status.Error != nil || status.Run.IsFailure() {
+			return &exitCodeError{code: 1}
+		}
+	}
+	return nil
+}
+
+// repositoryStatus holds the latest runs of the workflows of a repository, or the error fetching it
+type repositoryStatus struct {
+	statuses []workflowStatus
+	warnings []string
+}
+
+// fetchStatuses fetches the latest runs of the repositories concurrently. Repositories that could not
+// be fetched are listed as error rows, workflows whose runs could not be fetched are reported on stderr,
+// failed is set when there are any.
+func fetchStatuses(client *github.Client, cfg config.GithubConfig, names []string, opts *statusOptions) (statuses []workflowStatus, failed bool) {
+	results := make([]repositoryStatus, len(names))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, statusConcurrency)
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = fetchRepositoryStatus(client, cfg.FilterFor(name), name, opts)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		for _, warning := range result.warnings {
+			fmt.Fprintln(os.Stderr, warning)
+			failed = true
+		}
+		statuses = append(statuses, result.statuses...)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Repository != statuses[j].Repository {
+			return statuses[i].Repository < statuses[j].Repository
+		}
+		return statuses[i].workflowName() < statuses[j].workflowName()
+	})
+	return statuses, failed
+}
+
+func fetchRepositoryStatus(client *github.Client, filter *config.Filter, name string, opts *statusOptions) repositoryStatus {
+	owner, repo, ok := strings.Cut(name, "/")
+	if !ok {
+		err := fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", name)
+		return repositoryStatus{statuses: []workflowStatus{{Repository: name, Error: err}}}
+	}
+
+	repository, err := client.FetchWorkflowsWithLatestRun(owner, repo, opts.branch, filter)
+	if err != nil {
+		return repositoryStatus{statuses: []workflowStatus{{Repository: name, Error: err}}}
+	}
+
+	var result repositoryStatus
+	for _, workflow := range repository.Workflows {
+		if workflow.Error != nil {
+			result.warnings = append(result.warnings,
+				fmt.Sprintf("failed to fetch the runs of %s in %s: %v", workflow.Name, repository.FullName, workflow.Error))
+			continue
+		}
+		if len(workflow.Runs) == 0 {
+			continue
+		}
+		if len(opts.workflows) > 0 && !slices.ContainsFunc(opts.workflows, func(name string) bool {
+			return strings.EqualFold(name, workflow.Name)
+		}) {
+			continue
+		}
+		result.statuses = append(result.statuses, workflowStatus{
+			Repository: repository.FullName,
+			Workflow:   workflow,
+			Run:        workflow.Runs[0],
+		})
+	}
+	return result
+}
+
+func printStatuses(t term.Term, statuses []workflowStatus) error {
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+
+	tp := tableprinter.New(t.Out(), t.IsTerminalOutput(), width)
+	tp.AddHeader([]string{"", "REPOSITORY", "WORKFLOW", "STATUS", "BRANCH", "EVENT", "AGE"})
+	for _, status := range statuses {
+		if status.Error != nil {
+			addErrorRow(tp, status)
+			continue
+		}
+		run := status.Run
+		symbol, style := runStatusSymbol(run.Status, run.Conclusion)
+		colorize := tableprinter.WithColor(func(s string) string {
+			return style.Render(s)
+		})
+
+		state := run.Conclusion
+		if run.Status != "completed" {
+			state = run.Status
+		}
+
+		tp.AddField(symbol, colorize)
+		tp.AddField(status.Repository)
+		tp.AddField(status.Workflow.Name)
+		tp.AddField(state, colorize)
+		tp.AddField(run.HeadBranch)
+		tp.AddField(run.Event)
+		tp.AddField(utils.FormatTime(run.CreatedAt))
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// addErrorRow lists a repository that could not be fetched, with the error in place of the branch
+func addErrorRow(tp tableprinter.TablePrinter, status workflowStatus) {
+	symbol, style := runStatusSymbol("completed", "failure")
+	colorize := tableprinter.WithColor(func(s string) string {
+		return style.Render(s)
+	})
+	message, _, _ := strings.Cut(status.Error.Error(), "\n")
+
+	tp.AddField(symbol, colorize)
+	tp.AddField(status.Repository)
+	tp.AddField("")
+	tp.AddField(github.ShortError(status.Error), colorize)
+	tp.AddField(message)
+	tp.AddField("")
+	tp.AddField("")
+	tp.EndRow()
+}
+
+// runStatusSymbol returns a plain symbol for a run or job status and the style to color it,
+// command output does not rely on Nerd Font glyphs
+func runStatusSymbol(status, conclusion string) (string, lipgloss.Style) {
+	style := lipgloss.NewStyle()
+	if status != "completed" {
+		return "*", style.Foreground(lipgloss.Color("3"))
+	}
+	switch {
+	case conclusion == "success":
+		return "✓", style.Foreground(lipgloss.Color("2"))
+	case github.IsFailureConclusion(conclusion):
+		return "X", style.Foreground(lipgloss.Color("1"))
+	case conclusion == "skipped":
+		return "-", style.Foreground(lipgloss.Color("8"))
+	default:
+		return "-", style.Foreground(lipgloss.Color("3"))
+	}
+}
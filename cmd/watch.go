@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/notify"
+	"github.com/cpaluszek/gh-ci/ui/watch"
+	"github.com/spf13/cobra"
+)
+
+type watchOptions struct {
+	branch   string
+	sha      string
+	interval time.Duration
+	logLines int
+	notify   bool
+}
+
+func newWatchCmd() *cobra.Command {
+	opts := &watchOptions{}
+	cmd := &cobra.Command{
+		Use:   "watch [<run-url>]",
+		Short: "Watch runs until they complete",
+		Long: `Watch one or more workflow runs until they complete.
+
+Without a run URL, the runs of the latest commit of the current branch are watched,
+or those of --branch or --sha. Logs of failed steps are printed once every run completes
+and the command exits with a non-zero status when any run did not succeed.
+A notification is sent as each run completes when it matches the notification rules of the config,
+the failures of the default branch by default.
+Failed refreshes are retried with a growing delay, watching stops after 5 in a row.`,
+		Example: `  git push && gh ci watch
+  gh ci watch https://github.com/owner/repo/actions/runs/123
+  gh ci watch --repo owner/repo --branch main`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWatch(opts, args)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "watch the runs of the latest commit of this branch")
+	cmd.Flags().StringVar(&opts.sha, "sha", "", "watch the runs of this commit")
+	cmd.Flags().DurationVarP(&opts.interval, "interval", "i", 5*time.Second, "refresh interval")
+	cmd.Flags().IntVar(&opts.logLines, "log-lines", 20, "number of log lines printed for each failed step")
+	cmd.Flags().BoolVar(&opts.notify, "notify", true, "notify the completed runs matching the notification rules")
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newWatchCmd())
+}
+
+func runWatch(opts *watchOptions, args []string) error {
+	client, err := github.NewClient()
+	if err != nil {
+		return err
+	}
+
+	owner, repo, runs, err := resolveRuns(client, opts, args)
+	if err != nil {
+		return err
+	}
+
+	themeName, err := config.LoadThemeName(profileFlag)
+	if err != nil {
+		return err
+	}
+	theme, err := loadTheme(themeName)
+	if err != nil {
+		return err
+	}
+
+	var notifier notify.Backend
+	var rules config.NotificationsConfig
+	var defaultBranch string
+	if opts.notify {
+		rules, err = config.LoadNotificationsConfig()
+		if err != nil {
+			return err
+		}
+		notifier = notify.New(rules)
+	}
+	if notifier != nil {
+		// Rules may select the runs of the default branch
+		info, err := client.FetchRepositoryInfo(owner, repo)
+		if err != nil {
+			return err
+		}
+		defaultBranch = info.DefaultBranch
+	}
+
+	// Keep the inline rendering clean
+	log.SetOutput(io.Discard)
+
+	p := tea.NewProgram(watch.NewModel(client, owner, repo, runs, opts.interval, theme, notifier, rules, defaultBranch))
+	final, err := p.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run program: %w", err)
+	}
+
+	m := final.(watch.Model)
+	if m.Err() != nil {
+		return m.Err()
+	}
+	if m.Interrupted {
+		return &exitCodeError{code: 130}
+	}
+
+	excerpts, err := watch.FailedStepExcerpts(client, owner, repo, m.Runs(), opts.logLines)
+	fmt.Print(excerpts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to fetch logs:", err)
+	}
+
+	for _, run := range m.Runs() {
+		if run.Conclusion != "success" && run.Conclusion != "skipped" && run.Conclusion != "neutral" {
+			return &exitCodeError{code: 1}
+		}
+	}
+	return nil
+}
+
+// resolveRuns returns the runs to watch from a run URL, or from the commit selected by the flags
+func resolveRuns(client *github.Client, opts *watchOptions, args []string) (string, string, []*github.WorkflowRun, error) {
+	if len(args) == 1 {
+		info, err := github.ParseGitHubURL(args[0])
+		if err != nil {
+			return "", "", nil, err
+		}
+		id, err := strconv.ParseInt(info.RunID, 10, 64)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("invalid run ID %s: %w", info.RunID, err)
+		}
+		return info.User, info.Repo, []*github.WorkflowRun{{ID: id}}, nil
+	}
+
+	fullName := repoFlag
+	if fullName == "" {
+		current, ok := currentRepository()
+		if !ok {
+			return "", "", nil, fmt.Errorf("not in a git checkout, use a run URL or --repo")
+		}
+		fullName = current
+	}
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return "", "", nil, fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", fullName)
+	}
+
+	branch := opts.branch
+	if branch == "" && opts.sha == "" {
+		branch = currentBranch()
+	}
+
+	runs, err := client.FetchRunsForCommit(owner, repo, branch, opts.sha)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if len(runs) == 0 {
+		return "", "", nil, fmt.Errorf("no runs found for %s", fullName)
+	}
+	return owner, repo, runs, nil
+}
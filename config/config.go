@@ -1,15 +1,21 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrNoConfig is returned by Load when there is no config file yet
+var ErrNoConfig = errors.New("no config file")
+
 const (
 	ConfigDirName  = "gh-ci"
 	ConfigFileName = "config"
@@ -18,37 +24,287 @@ const (
 
 type Config struct {
 	Github GithubConfig
+	Cache  CacheConfig
+	// Keys rebinds the actions of the TUI, e.g. 'select: [enter, l]'
+	Keys map[string][]string `yaml:"keys,omitempty"`
+	// Theme is the name of a built-in theme or of a YAML file of the themes directory
+	Theme string `yaml:"theme,omitempty"`
+	// Profiles are named selections of groups, applied with the --profile flag
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+	// Notifications select the completed runs notified and how
+	Notifications NotificationsConfig `yaml:"notifications,omitempty"`
 }
 
 type GithubConfig struct {
+	// Repositories entries are either 'owner/repo', 'owner/pattern' globs, 'org:name' or 'user:name'
 	Repositories []string
+	// Groups are named lists of repository entries, listed under a header of their own
+	Groups map[string][]string `yaml:"groups,omitempty"`
+	// Topics restricts discovered repositories to those having at least one of these topics
+	Topics []string
+	// Exclude lists 'owner/repo' globs removed from the resolved repositories
+	Exclude []string
+	// Pinned lists 'owner/repo' names listed first, in this order
+	Pinned []string `yaml:"pinned,omitempty"`
+	// PinnedWorkflows lists 'owner/repo:workflow name' entries whose runs are listed first, in this order
+	PinnedWorkflows []string `mapstructure:"pinned_workflows" yaml:"pinned_workflows,omitempty"`
+	// Filters hide workflows and runs per repository, they are not fetched at all
+	Filters []Filter `yaml:"filters,omitempty"`
+}
+
+type CacheConfig struct {
+	// MaxSize limits the disk usage of the cache, e.g. '500MB', the least recently used entries are evicted beyond it.
+	// A size of 0 disables the limit.
+	MaxSize string `mapstructure:"max_size" yaml:"max_size"`
+}
+
+// MaxSizeBytes returns the configured cache size limit, zero when it is disabled.
+// set is false when the config leaves the default limit.
+func (c CacheConfig) MaxSizeBytes() (size int64, set bool, err error) {
+	if c.MaxSize == "" {
+		return 0, false, nil
+	}
+	size, err = ParseSize(c.MaxSize)
+	return size, err == nil, err
+}
+
+// ParseSize parses a size in bytes with an optional KB, MB or GB suffix, zero is accepted
+func ParseSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if value, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, multiplier = strings.TrimSpace(value), unit.multiplier
+			break
+		}
+	}
+
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size '%s', expected a non-negative number with an optional KB, MB or GB suffix", size)
+	}
+	return value * multiplier, nil
+}
+
+type EntryKind int
+
+const (
+	RepositoryEntry EntryKind = iota
+	PatternEntry
+	OrganizationEntry
+	UserEntry
+)
+
+// Entry is a parsed element of the repositories list
+type Entry struct {
+	Kind  EntryKind
+	Owner string
+	// Name is the repository name or glob pattern, empty for organization and user entries
+	Name string
+}
+
+// IsDiscovery reports whether the entry has to be resolved through the GitHub API
+func (e Entry) IsDiscovery() bool {
+	return e.Kind != RepositoryEntry
+}
+
+func ParseEntry(entry string) (Entry, error) {
+	if len(entry) == 0 {
+		return Entry{}, fmt.Errorf("repository name cannot be empty")
+	}
+
+	if owner, ok := strings.CutPrefix(entry, "org:"); ok {
+		if len(owner) == 0 || strings.Contains(owner, "/") {
+			return Entry{}, fmt.Errorf("invalid organization entry '%s', expected 'org:name'", entry)
+		}
+		return Entry{Kind: OrganizationEntry, Owner: owner}, nil
+	}
+	if owner, ok := strings.CutPrefix(entry, "user:"); ok {
+		if len(owner) == 0 || strings.Contains(owner, "/") {
+			return Entry{}, fmt.Errorf("invalid user entry '%s', expected 'user:name'", entry)
+		}
+		return Entry{Kind: UserEntry, Owner: owner}, nil
+	}
+
+	parts := strings.Split(entry, "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return Entry{}, fmt.Errorf("repository name must be in the format 'owner/repo'")
+	}
+	if _, err := path.Match(parts[1], ""); err != nil {
+		return Entry{}, fmt.Errorf("invalid repository pattern '%s': %w", entry, err)
+	}
+	if strings.ContainsAny(parts[1], "*?[") {
+		return Entry{Kind: PatternEntry, Owner: parts[0], Name: parts[1]}, nil
+	}
+	return Entry{Kind: RepositoryEntry, Owner: parts[0], Name: parts[1]}, nil
+}
+
+// MatchPattern reports whether the full name of a repository matches an 'owner/repo' glob, ignoring case
+func MatchPattern(pattern, fullName string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(fullName))
+	return err == nil && matched
 }
 
 func Load() (*Config, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := read(configDir)
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return nil, fmt.Errorf("%w in %s, run gh ci to create one", ErrNoConfig, configDir)
+		}
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadForRepository reads the config file if there is one and restricts it to a single repository.
+// Organization, user and pattern entries are rejected, they would select several repositories.
+func LoadForRepository(repo string) (*Config, error) {
+	entry, err := ParseEntry(repo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository: %w", err)
+	}
+	if entry.Kind != RepositoryEntry {
+		return nil, fmt.Errorf("invalid repository '%s', expected a single repository in the 'owner/repo' format", repo)
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := read(configDir)
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, err
+		}
+		cfg = &Config{}
+	}
+
+	cfg.Github.Repositories = []string{repo}
+	cfg.Github.Groups = nil
+	cfg.Github.Exclude = nil
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid repository: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadCacheConfig reads the cache settings of the config file, without validating the
+// repositories so that the cache can be managed whatever the state of the config
+func LoadCacheConfig() (CacheConfig, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return CacheConfig{}, err
+	}
+
+	cfg, err := read(configDir)
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return CacheConfig{}, nil
+		}
+		return CacheConfig{}, err
+	}
+
+	if _, _, err := cfg.Cache.MaxSizeBytes(); err != nil {
+		return CacheConfig{}, fmt.Errorf("invalid cache max_size: %w", err)
+	}
+	return cfg.Cache, nil
+}
+
+// LoadNotificationsConfig reads the notification settings of the config file, without validating
+// the repositories so that commands working without a config file can notify
+func LoadNotificationsConfig() (NotificationsConfig, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return NotificationsConfig{}, err
+	}
+
+	cfg, err := read(configDir)
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return NotificationsConfig{}, nil
+		}
+		return NotificationsConfig{}, err
+	}
+
+	if err := cfg.Notifications.validate(); err != nil {
+		return NotificationsConfig{}, fmt.Errorf("invalid notifications: %w", err)
+	}
+	return cfg.Notifications, nil
+}
+
+// LoadThemeName reads the theme of the config file, replaced by the theme of profile if set,
+// without validating the repositories so that commands working without a config file are themed
+func LoadThemeName(profile string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := read(configDir)
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if profile != "" {
+		if err := cfg.UseProfile(profile); err != nil {
+			return "", err
+		}
+	}
+	return cfg.Theme, nil
+}
+
+// ThemesDir returns the directory holding the theme files
+func ThemesDir() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "themes"), nil
+}
+
+func getConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("error finding home directory: %w", err)
+		return "", fmt.Errorf("error finding home directory: %w", err)
 	}
 
 	configHome := os.Getenv("XDG_CONFIG_HOME")
 	if configHome == "" {
 		configHome = filepath.Join(home, ".config")
 	}
-	configDir := filepath.Join(configHome, ConfigDirName)
+	return filepath.Join(configHome, ConfigDirName), nil
+}
 
+// read loads the config file without validating it, a viper.ConfigFileNotFoundError
+// is returned as is when the file does not exist
+func read(configDir string) (*Config, error) {
 	viper.AddConfigPath(configDir)
 	viper.SetConfigName(ConfigFileName)
 	viper.SetConfigType(ConfigFileExt)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			if err := createDefaultConfig(configDir); err != nil {
-				return nil, fmt.Errorf("failed to create default config: %w", err)
-			}
-			return nil, fmt.Errorf("default config created at %s", configDir)
-		} else {
-			return nil, fmt.Errorf("error reading config file: %w", err)
+			return nil, err
 		}
+		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
 
 	var cfg Config
@@ -56,40 +312,90 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
-	}
-
 	return &cfg, nil
 }
 
 func (c *Config) Validate() error {
-	if len(c.Github.Repositories) == 0 {
+	if len(c.Github.Entries()) == 0 {
 		return fmt.Errorf("no repositories found in config")
 	}
 	for _, repo := range c.Github.Repositories {
-		if len(repo) == 0 {
-			return fmt.Errorf("repository name cannot be empty")
+		if _, err := ParseEntry(repo); err != nil {
+			return err
 		}
-		parts := strings.Split(repo, "/")
-		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
-			return fmt.Errorf("repository name must be in the format 'owner/repo'")
+	}
+	for name, entries := range c.Github.Groups {
+		for _, repo := range entries {
+			if _, err := ParseEntry(repo); err != nil {
+				return fmt.Errorf("invalid entry of group '%s': %w", name, err)
+			}
+		}
+	}
+	for name, profile := range c.Profiles {
+		for _, group := range profile.Groups {
+			if _, ok := c.Github.Groups[group]; !ok {
+				return fmt.Errorf("profile '%s' selects unknown group '%s'", name, group)
+			}
+		}
+	}
+	for _, pattern := range c.Github.Exclude {
+		entry, err := ParseEntry(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid exclude entry: %w", err)
+		}
+		if entry.Kind == OrganizationEntry || entry.Kind == UserEntry {
+			return fmt.Errorf("exclude entries must be in the format 'owner/repo', got '%s'", pattern)
+		}
+	}
+	for _, name := range c.Github.Pinned {
+		entry, err := ParseEntry(name)
+		if err != nil {
+			return fmt.Errorf("invalid pinned entry: %w", err)
+		}
+		if entry.Kind != RepositoryEntry {
+			return fmt.Errorf("pinned entries must be in the format 'owner/repo', got '%s'", name)
 		}
 	}
+	for _, pin := range c.Github.PinnedWorkflows {
+		repo, workflow, ok := strings.Cut(pin, ":")
+		if entry, err := ParseEntry(repo); !ok || err != nil || entry.Kind != RepositoryEntry || workflow == "" {
+			return fmt.Errorf("pinned workflows must be in the format 'owner/repo:workflow name', got '%s'", pin)
+		}
+	}
+	for _, filter := range c.Github.Filters {
+		if err := filter.validate(); err != nil {
+			return err
+		}
+	}
+	for _, topic := range c.Github.Topics {
+		if len(topic) == 0 {
+			return fmt.Errorf("topic cannot be empty")
+		}
+	}
+	if _, _, err := c.Cache.MaxSizeBytes(); err != nil {
+		return fmt.Errorf("invalid cache max_size: %w", err)
+	}
+	if err := c.Notifications.validate(); err != nil {
+		return fmt.Errorf("invalid notifications: %w", err)
+	}
 	return nil
 }
 
-func createDefaultConfig(configDir string) error {
+// Create writes a config file following the given repositories, with the other settings
+// commented out, and returns its path. An existing config file is never overwritten.
+func Create(repositories []string) (configPath string, err error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
 	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configPath := filepath.Join(configDir, fmt.Sprintf("%s.%s", ConfigFileName, ConfigFileExt))
-
-	yaml, _ := yaml.Marshal(Config{})
-	newConfigFile, err := os.OpenFile(configPath, os.O_RDWR|os.O_CREATE, 0644)
+	configPath = filepath.Join(configDir, fmt.Sprintf("%s.%s", ConfigFileName, ConfigFileExt))
+	newConfigFile, err := os.OpenFile(configPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to create config file: %w", err)
+		return "", fmt.Errorf("failed to create config file: %w", err)
 	}
 	defer func() {
 		closeErr := newConfigFile.Close()
@@ -98,14 +404,59 @@ func createDefaultConfig(configDir string) error {
 		}
 	}()
 
-	_, err = newConfigFile.WriteString(string(yaml))
-	if err != nil {
-		return fmt.Errorf("failed to write to config file: %w", err)
+	var repos strings.Builder
+	for _, repo := range repositories {
+		entry, _ := yaml.Marshal(repo)
+		fmt.Fprintf(&repos, "    - %s", entry)
 	}
+	if _, err = fmt.Fprintf(newConfigFile, configTemplate, repos.String()); err != nil {
+		return "", fmt.Errorf("failed to write to config file: %w", err)
+	}
+	return configPath, nil
+}
 
-	fmt.Printf("Created default configuration at: %s\n", configPath)
-	fmt.Println("Please update it with your repository names.")
-	fmt.Println("Example: \n  - owner/repo1\n  - owner/repo2")
+// configTemplate documents every setting of the config file, %s being the repository entries
+const configTemplate = `# gh-ci configuration, changes are applied while gh ci runs
 
-	return nil
-}
+# default, ascii, high-contrast, light or a file of the themes directory
+# theme: default
+
+# Key overrides of any action
+# keys:
+#   select: [enter, l]
+
+# The least recently used logs are evicted beyond this size, 0 disables the limit
+# cache:
+#   max_size: 500MB
+
+# Notify the failures of default branches, fetching the repositories every 5 minutes
+# notifications:
+#   backend: auto
+#   interval: 5m
+
+# Named selections of groups, theme and filters, used with --profile
+# profiles:
+#   ops:
+#     groups: [backend]
+#     theme: high-contrast
+
+github:
+  # Only keep the discovered repositories having one of these topics
+  # topics: [backend]
+
+  # Repositories removed from the list, globs allowed
+  # exclude: [owner/legacy-*]
+
+  # Repositories listed under a collapsible header
+  # groups:
+  #   backend: [owner/api, owner/svc-*]
+
+  # Workflows and runs hidden per repository glob, press H to show them
+  # filters:
+  #   - repository: "*/*"
+  #     exclude_workflows: [CodeQL, dynamic/*/*]
+  #     exclude_actors: ["dependabot[bot]"]
+
+  # 'owner/repo', 'owner/pattern-*' globs, 'org:name' or 'user:name'
+  repositories:
+%s`
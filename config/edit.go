@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Read reads the config file loaded by Load again, applying the profile when it is not empty
+func Read(profile string) (*Config, error) {
+	return reread(viper.ConfigFileUsed(), profile)
+}
+
+func reread(path, profile string) (*Config, error) {
+	cfg, err := decode(path)
+	if err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		if err := cfg.UseProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// AddRepository appends an 'owner/repo' entry to a group of the config file,
+// or to the ungrouped repositories when group is empty
+func AddRepository(fullName, group string) error {
+	return editFile(func(root *yaml.Node) error {
+		github := mappingValue(root, "github", yaml.MappingNode)
+		list := mappingValue(github, "repositories", yaml.SequenceNode)
+		if group != "" {
+			list = mappingValue(mappingValue(github, "groups", yaml.MappingNode), group, yaml.SequenceNode)
+		}
+		if slices.ContainsFunc(list.Content, equalFold(fullName)) {
+			return fmt.Errorf("%s is already in the config", fullName)
+		}
+		appendScalar(list, fullName)
+		return nil
+	})
+}
+
+// RemoveRepository removes the entries of a repository from the config file, along with its pin.
+// A repository still matching an organization, user or pattern entry is excluded instead.
+func RemoveRepository(fullName string) error {
+	return editFile(func(root *yaml.Node) error {
+		github := mappingValue(root, "github", yaml.MappingNode)
+		lists := []*yaml.Node{mappingValue(github, "repositories", yaml.SequenceNode)}
+		if groups := findValue(github, "groups"); groups != nil && groups.Kind == yaml.MappingNode {
+			for i := 1; i < len(groups.Content); i += 2 {
+				lists = append(lists, groups.Content[i])
+			}
+		}
+
+		matched := false
+		for _, list := range lists {
+			list.Content = slices.DeleteFunc(list.Content, equalFold(fullName))
+			for _, entry := range list.Content {
+				matched = matched || MatchEntry(entry.Value, fullName)
+			}
+		}
+		if pinned := findValue(github, "pinned"); pinned != nil {
+			pinned.Content = slices.DeleteFunc(pinned.Content, equalFold(fullName))
+		}
+		if pinned := findValue(github, "pinned_workflows"); pinned != nil {
+			pinned.Content = slices.DeleteFunc(pinned.Content, func(node *yaml.Node) bool {
+				repo, _, _ := strings.Cut(node.Value, ":")
+				return strings.EqualFold(repo, fullName)
+			})
+		}
+		if matched {
+			appendScalar(mappingValue(github, "exclude", yaml.SequenceNode), fullName)
+		}
+		return nil
+	})
+}
+
+// SetPinned replaces the pinned repositories of the config file, in display order
+func SetPinned(names []string) error {
+	return setGithubList("pinned", names)
+}
+
+// SetPinnedWorkflows replaces the pinned workflows of the config file, in display order
+func SetPinnedWorkflows(pins []string) error {
+	return setGithubList("pinned_workflows", pins)
+}
+
+// setGithubList replaces a list of the github section of the config file
+func setGithubList(key string, values []string) error {
+	return editFile(func(root *yaml.Node) error {
+		github := mappingValue(root, "github", yaml.MappingNode)
+		list := mappingValue(github, key, yaml.SequenceNode)
+		list.Content = nil
+		for _, value := range values {
+			appendScalar(list, value)
+		}
+		return nil
+	})
+}
+
+// editFile applies edit to the top-level mapping of the config file and writes it back.
+// Going through the node API keeps the comments and the order of the keys.
+func editFile(edit func(root *yaml.Node) error) error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return fmt.Errorf("no config file loaded")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file is not a mapping")
+	}
+	if err := edit(root); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	// Replace the file at once, so that the watcher never reads it half written,
+	// writing next to the target of a symlinked config
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if err := writeFileAtomic(path, out.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write to config file: %w", err)
+	}
+	return nil
+}
+
+// findValue returns the value of a key of a mapping node, nil when the key is missing
+func findValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value of a key of a mapping node, adding the key when it is missing.
+// Empty values, such as a key left without entries, are turned into a node of the given kind.
+func mappingValue(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	value := findValue(mapping, key)
+	if value == nil {
+		value = &yaml.Node{}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+	if value.Kind != kind {
+		value.Kind = kind
+		value.Tag = ""
+		value.Value = ""
+		value.Content = nil
+	}
+	return value
+}
+
+func appendScalar(sequence *yaml.Node, value string) {
+	sequence.Content = append(sequence.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+func equalFold(fullName string) func(node *yaml.Node) bool {
+	return func(node *yaml.Node) bool {
+		return strings.EqualFold(node.Value, fullName)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Filter hides workflows and runs of the repositories matching Repository
+type Filter struct {
+	// Repository is an 'owner/repo' glob, '*/*' matches every repository
+	Repository string
+	// IncludeWorkflows keeps only the workflows matching one of these name or file path globs
+	IncludeWorkflows []string `mapstructure:"include_workflows" yaml:"include_workflows,omitempty"`
+	// ExcludeWorkflows hides the workflows matching one of these name or file path globs
+	ExcludeWorkflows []string `mapstructure:"exclude_workflows" yaml:"exclude_workflows,omitempty"`
+	// ExcludeEvents hides the runs triggered by these events, e.g. 'schedule'
+	ExcludeEvents []string `mapstructure:"exclude_events" yaml:"exclude_events,omitempty"`
+	// ExcludeActors hides the runs triggered by these logins, e.g. 'dependabot[bot]'
+	ExcludeActors []string `mapstructure:"exclude_actors" yaml:"exclude_actors,omitempty"`
+}
+
+// FilterFor merges the filters matching a repository, nil when none does
+func (c GithubConfig) FilterFor(fullName string) *Filter {
+	var merged *Filter
+	for _, filter := range c.Filters {
+		if !MatchPattern(filter.Repository, fullName) {
+			continue
+		}
+		if merged == nil {
+			merged = &Filter{Repository: fullName}
+		}
+		merged.IncludeWorkflows = append(merged.IncludeWorkflows, filter.IncludeWorkflows...)
+		merged.ExcludeWorkflows = append(merged.ExcludeWorkflows, filter.ExcludeWorkflows...)
+		merged.ExcludeEvents = append(merged.ExcludeEvents, filter.ExcludeEvents...)
+		merged.ExcludeActors = append(merged.ExcludeActors, filter.ExcludeActors...)
+	}
+	return merged
+}
+
+// KeepWorkflow reports whether a workflow, given by its name and file path, is shown.
+// A nil filter keeps everything.
+func (f *Filter) KeepWorkflow(name, filePath string) bool {
+	if f == nil {
+		return true
+	}
+	matches := func(pattern string) bool {
+		return matchGlob(pattern, name) || matchGlob(pattern, filePath)
+	}
+	if len(f.IncludeWorkflows) > 0 && !slices.ContainsFunc(f.IncludeWorkflows, matches) {
+		return false
+	}
+	return !slices.ContainsFunc(f.ExcludeWorkflows, matches)
+}
+
+// HidesRuns reports whether the filter rejects runs, in addition to workflows
+func (f *Filter) HidesRuns() bool {
+	return f != nil && (len(f.ExcludeEvents) > 0 || len(f.ExcludeActors) > 0)
+}
+
+// KeepRun reports whether a run, given by its event and the login of its actor, is shown.
+// A nil filter keeps everything.
+func (f *Filter) KeepRun(event, actor string) bool {
+	if f == nil {
+		return true
+	}
+	if slices.Contains(f.ExcludeEvents, event) {
+		return false
+	}
+	return !slices.ContainsFunc(f.ExcludeActors, func(excluded string) bool {
+		return strings.EqualFold(excluded, actor)
+	})
+}
+
+func (f Filter) validate() error {
+	if f.Repository == "" {
+		return fmt.Errorf("filters must set a repository glob")
+	}
+	if _, err := path.Match(f.Repository, ""); err != nil {
+		return fmt.Errorf("invalid filter repository '%s': %w", f.Repository, err)
+	}
+	for _, pattern := range slices.Concat(f.IncludeWorkflows, f.ExcludeWorkflows) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid workflow pattern '%s' for '%s': %w", pattern, f.Repository, err)
+		}
+	}
+	return nil
+}
+
+func matchGlob(pattern, value string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
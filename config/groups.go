@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Profile selects the groups, theme and filters used for a context, e.g. a product
+type Profile struct {
+	// Groups lists the groups of the profile, the ungrouped repositories are left out
+	Groups []string
+	// Theme replaces the theme of the config when set
+	Theme string `yaml:"theme,omitempty"`
+	// Topics replaces the topics of the config when set
+	Topics []string `yaml:"topics,omitempty"`
+	// Exclude is added to the excluded repositories of the config
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// GroupNames returns the names of the groups in display order
+func (c GithubConfig) GroupNames() []string {
+	names := make([]string, 0, len(c.Groups))
+	for name := range c.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Entries returns the ungrouped repository entries followed by those of every group
+func (c GithubConfig) Entries() []string {
+	entries := append([]string(nil), c.Repositories...)
+	for _, name := range c.GroupNames() {
+		entries = append(entries, c.Groups[name]...)
+	}
+	return entries
+}
+
+// CacheScope returns the entry selected by the config when it selects a single one, e.g. the
+// repository of the current git checkout, '*' otherwise. Cache keys carry it after their kind
+// so that the cache usage can be attributed to the repository.
+func (c GithubConfig) CacheScope() string {
+	if entries := c.Entries(); len(entries) == 1 {
+		return entries[0]
+	}
+	return "*"
+}
+
+// GroupOf returns the first group, in display order, having an entry matching the
+// repository, or an empty string when the repository is not part of any group
+func (c GithubConfig) GroupOf(fullName string) string {
+	for _, name := range c.GroupNames() {
+		for _, entry := range c.Groups[name] {
+			if MatchEntry(entry, fullName) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// PinIndex returns the position of a repository among the pinned ones, -1 when it is not pinned
+func (c GithubConfig) PinIndex(fullName string) int {
+	return slices.IndexFunc(c.Pinned, func(name string) bool {
+		return strings.EqualFold(name, fullName)
+	})
+}
+
+// WorkflowPin returns the entry pinning a workflow of a repository in PinnedWorkflows
+func WorkflowPin(fullName, workflow string) string {
+	return fullName + ":" + workflow
+}
+
+// WorkflowPinIndex returns the position of a workflow among the pinned ones, -1 when it is not pinned.
+// Repository names are compared ignoring case, workflow names as is.
+func (c GithubConfig) WorkflowPinIndex(fullName, workflow string) int {
+	return slices.IndexFunc(c.PinnedWorkflows, func(pin string) bool {
+		repo, name, _ := strings.Cut(pin, ":")
+		return strings.EqualFold(repo, fullName) && name == workflow
+	})
+}
+
+// MatchEntry reports whether a repository is selected by an entry of the repositories list
+func MatchEntry(entry, fullName string) bool {
+	parsed, err := ParseEntry(entry)
+	if err != nil {
+		return false
+	}
+	switch parsed.Kind {
+	case OrganizationEntry, UserEntry:
+		owner, _, _ := strings.Cut(fullName, "/")
+		return strings.EqualFold(owner, parsed.Owner)
+	default:
+		return MatchPattern(entry, fullName)
+	}
+}
+
+// UseProfile restricts the config to the groups of a profile and applies its theme and filters
+func (c *Config) UseProfile(name string) error {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		var names []string
+		for known := range c.Profiles {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile '%s', no profiles in config", name)
+		}
+		return fmt.Errorf("unknown profile '%s', expected one of: %s", name, strings.Join(names, ", "))
+	}
+
+	groups := make(map[string][]string, len(profile.Groups))
+	for _, group := range profile.Groups {
+		entries, ok := c.Github.Groups[group]
+		if !ok {
+			return fmt.Errorf("profile '%s' selects unknown group '%s'", name, group)
+		}
+		groups[group] = entries
+	}
+	c.Github.Repositories = nil
+	c.Github.Groups = groups
+	if profile.Theme != "" {
+		c.Theme = profile.Theme
+	}
+	if len(profile.Topics) > 0 {
+		c.Github.Topics = profile.Topics
+	}
+	c.Github.Exclude = append(append([]string(nil), c.Github.Exclude...), profile.Exclude...)
+	return nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"time"
+)
+
+// Notification backends, BackendAuto uses the desktop when available and the terminal otherwise
+const (
+	BackendAuto     = "auto"
+	BackendDesktop  = "desktop"
+	BackendTerminal = "terminal"
+	BackendNone     = "none"
+)
+
+// DefaultBranch matches the default branch of a repository in the branches of a notification rule
+const DefaultBranch = "default"
+
+// minNotificationInterval keeps the polling of the TUI within the API rate limits
+const minNotificationInterval = 30 * time.Second
+
+type NotificationsConfig struct {
+	// Backend is auto, desktop, terminal or none, auto when empty
+	Backend string `yaml:"backend,omitempty"`
+	// Interval is how often the TUI fetches the repositories again to notify, e.g. '5m', never when empty
+	Interval string `yaml:"interval,omitempty"`
+	// Rules select the completed runs notified by the TUI, failures of default branches when empty
+	Rules []NotificationRule `yaml:"rules,omitempty"`
+}
+
+// NotificationRule matches completed runs, an empty list matches everything
+type NotificationRule struct {
+	// Repositories are 'owner/repo' globs
+	Repositories []string `yaml:"repositories,omitempty"`
+	// Branches are branch globs, 'default' matches the default branch of the repository
+	Branches []string `yaml:"branches,omitempty"`
+	// Conclusions are run conclusions, e.g. 'failure' or 'success'
+	Conclusions []string `yaml:"conclusions,omitempty"`
+}
+
+// defaultRules notify the failures of default branches
+var defaultRules = []NotificationRule{{
+	Branches:    []string{DefaultBranch},
+	Conclusions: []string{"failure", "timed_out", "startup_failure"},
+}}
+
+// PollInterval returns how often the TUI fetches the repositories again, zero when it does not
+func (c NotificationsConfig) PollInterval() (time.Duration, error) {
+	if c.Interval == "" || c.BackendName() == BackendNone {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if interval < minNotificationInterval {
+		return 0, fmt.Errorf("interval must be at least %s, got %s", minNotificationInterval, c.Interval)
+	}
+	return interval, nil
+}
+
+// BackendName returns the configured backend, auto when none is set
+func (c NotificationsConfig) BackendName() string {
+	if c.Backend == "" {
+		return BackendAuto
+	}
+	return c.Backend
+}
+
+// Notifies reports whether a completed run of a repository matches one of the rules
+func (c NotificationsConfig) Notifies(fullName, defaultBranch, branch, conclusion string) bool {
+	rules := c.Rules
+	if len(rules) == 0 {
+		rules = defaultRules
+	}
+	return slices.ContainsFunc(rules, func(rule NotificationRule) bool {
+		return rule.matches(fullName, defaultBranch, branch, conclusion)
+	})
+}
+
+func (r NotificationRule) matches(fullName, defaultBranch, branch, conclusion string) bool {
+	if len(r.Repositories) > 0 && !slices.ContainsFunc(r.Repositories, func(pattern string) bool {
+		return MatchPattern(pattern, fullName)
+	}) {
+		return false
+	}
+	if len(r.Branches) > 0 && !slices.ContainsFunc(r.Branches, func(pattern string) bool {
+		if pattern == DefaultBranch {
+			return branch != "" && branch == defaultBranch
+		}
+		return matchGlob(pattern, branch)
+	}) {
+		return false
+	}
+	return len(r.Conclusions) == 0 || slices.Contains(r.Conclusions, conclusion)
+}
+
+func (c NotificationsConfig) validate() error {
+	switch c.BackendName() {
+	case BackendAuto, BackendDesktop, BackendTerminal, BackendNone:
+	default:
+		return fmt.Errorf("unknown backend '%s', expected auto, desktop, terminal or none", c.Backend)
+	}
+	if _, err := c.PollInterval(); err != nil {
+		return fmt.Errorf("invalid interval: %w", err)
+	}
+	for _, rule := range c.Rules {
+		for _, pattern := range slices.Concat(rule.Repositories, rule.Branches) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid rule pattern '%s': %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
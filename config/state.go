@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const stateFileName = "state.json"
+
+// SortState is the sort of a table saved across sessions, the column is stored by title
+// so that it survives changes of the column order. An empty column keeps the data order.
+type SortState struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+// State holds what the UI remembers across sessions. Unlike the cache it is never evicted.
+type State struct {
+	// Sorts are the sorts of the tables by section title
+	Sorts map[string]SortState `json:"sorts,omitempty"`
+}
+
+// stateMu serializes the updates of the state file within the process
+var stateMu sync.Mutex
+
+// LoadState reads the state file, an empty state when there is none
+func LoadState() (State, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return readState()
+}
+
+// UpdateState applies fn to the state file and saves it
+func UpdateState(fn func(state *State)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := readState()
+	if err != nil {
+		return err
+	}
+	fn(&state)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+func readState() (State, error) {
+	var state State
+	path, err := statePath()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// statePath returns the state file, under XDG_STATE_HOME or ~/.local/state
+func statePath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding home directory: %w", err)
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, ConfigDirName, stateFileName), nil
+}
+
+// writeFileAtomic writes data to a temporary file renamed over path,
+// so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Change is the config read again after the config file changed,
+// Error is set instead when the new config cannot be read or is invalid
+type Change struct {
+	Config *Config
+	Error  error
+}
+
+// Watch reports the changes of the config file read by Load on the returned channel,
+// the profile is applied to every new config when it is not empty
+func Watch(profile string) <-chan Change {
+	changes := make(chan Change, 1)
+
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		cfg, err := reread(event.Name, profile)
+		if err != nil {
+			changes <- Change{Error: err}
+			return
+		}
+		changes <- Change{Config: cfg}
+	})
+	viper.WatchConfig()
+
+	return changes
+}
+
+// decode reads a config file with a viper instance of its own,
+// so that errors are reported rather than the previous values kept
+func decode(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType(ConfigFileExt)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unable to decode into struct: %w", err)
+	}
+	return &cfg, nil
+}
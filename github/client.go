@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cpaluszek/gh-ci/config"
 )
 
 type Client struct {
@@ -22,6 +25,8 @@ const (
 	workflowsPerPage    = 20
 	workflowRunsPerPage = 20
 	jobsPerPage         = 10
+	commitRunsPerPage   = 50
+	jobsPageSize        = 100 // Largest page, to list every job of a run
 )
 
 type concurrentResult struct {
@@ -66,50 +71,199 @@ func NewClient() (*Client, error) {
 	}, nil
 }
 
-// FetchRepositoriesWithWorkflows fetches repositories that have GitHub Actions workflows
-func (c *Client) FetchRepositoriesWithWorkflows(names []string) ([]*Repository, error) {
-	repos, err := c.fetchRepositories(names)
+// RepositoryResult is the outcome of fetching a repository of a stream
+type RepositoryResult struct {
+	// Name is the requested 'owner/repo'
+	Name string
+	// Repository holds the name and the error of the repository when it could not be fetched
+	Repository *Repository
+	Error      error
+}
+
+// StreamRepositoriesWithWorkflows fetches repositories with their workflows concurrently and sends
+// each of them on the returned channel as soon as it is ready. The channel is closed once all are sent.
+// The filters of the config are applied to the workflows and runs of each repository.
+func (c *Client) StreamRepositoriesWithWorkflows(names []string, cfg config.GithubConfig) <-chan RepositoryResult {
+	results := make(chan RepositoryResult, len(names))
+
+	go func() {
+		defer close(results)
+
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, defaultConcurrency)
+		for _, name := range names {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				repository, err := c.FetchRepository(name, cfg.FilterFor(name))
+				results <- RepositoryResult{Name: name, Repository: repository, Error: err}
+			}()
+		}
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// FetchRepositoriesWithWorkflows fetches repositories that have GitHub Actions workflows.
+// Repositories that could not be fetched are returned with their error set.
+func (c *Client) FetchRepositoriesWithWorkflows(names []string, cfg config.GithubConfig) ([]*Repository, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no repository names provided")
+	}
+
+	var repos []*Repository
+	var fetched int
+	var networkErr error
+	for result := range c.StreamRepositoriesWithWorkflows(names, cfg) {
+		if result.Error != nil {
+			log.Printf("Error fetching %s: %v", result.Name, result.Error)
+			if IsNetworkError(result.Error) {
+				networkErr = result.Error
+			}
+		} else {
+			fetched++
+		}
+		repos = append(repos, result.Repository)
+	}
+
+	// GitHub is considered unreachable only when nothing could be fetched
+	if fetched == 0 && networkErr != nil {
+		return nil, networkErr
+	}
+
+	// Sort repositories by update time (most recent first)
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].UpdatedAt.After(repos[j].UpdatedAt)
+	})
+
+	return repos, nil
+}
+
+// FetchRepository fetches a repository given as 'owner/repo' with its workflows and their recent runs,
+// hiding those rejected by the filter, which may be nil.
+// On failure, the returned repository only holds its name and the error.
+func (c *Client) FetchRepository(fullName string, filter *config.Filter) (*Repository, error) {
+	owner, repo := parseFullName(fullName)
+	if owner == "" {
+		err := fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", fullName)
+		return &Repository{Name: fullName, FullName: fullName, Error: err}, err
+	}
+
+	repository, err := c.FetchWorkflowsWithRuns(owner, repo, filter)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching repositories: %w", err)
+		return &Repository{Name: repo, FullName: fullName, Error: err}, err
 	}
+	return repository, nil
+}
 
-	if len(repos) == 0 {
-		return nil, nil
+// FetchWorkflowsWithRuns fetches workflows and their recent runs for a repository.
+// The runs of the workflows rejected by the filter are not fetched, a nil filter keeps everything.
+func (c *Client) FetchWorkflowsWithRuns(owner, repo string, filter *config.Filter) (*Repository, error) {
+	// Fetch repository info first
+	repository, err := c.FetchRepositoryInfo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch workflows for the repository
+	requestUrl := fmt.Sprintf("repos/%s/%s/actions/workflows", owner, repo)
+	var workflowsResponse struct {
+		Workflows []Workflow `json:"workflows"`
+	}
+
+	err = c.Client.Get(requestUrl, &workflowsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch workflows for %s/%s: %w", owner, repo, err)
 	}
 
-	// Convert to interface slice for the generic function
-	repoItems := make([]interface{}, len(repos))
-	for i, repo := range repos {
-		repoItems[i] = repo
+	// Convert to interface slice, skipping the filtered workflows
+	var workflowItems []interface{}
+	for _, workflow := range workflowsResponse.Workflows {
+		if filter.KeepWorkflow(workflow.Name, workflow.Path) {
+			workflowItems = append(workflowItems, workflow)
+		}
 	}
 
-	results := runConcurrent(defaultConcurrency, repoItems, func(item any) (any, error) {
-		repo := item.(*Repository)
-		owner, repoName := parseFullName(repo.FullName)
-		return c.FetchWorkflowsWithRuns(owner, repoName)
+	results := runConcurrent(defaultConcurrency, workflowItems, func(item interface{}) (interface{}, error) {
+		workflow := item.(Workflow)
+		c.fetchWorkflowRuns(owner, repo, &workflow, filter)
+		return &workflow, nil // Return with error set but don't fail
 	})
 
 	// Process results
-	var successfulRepos []*Repository
+	var workflows []*Workflow
 	for _, res := range results {
-		if res.Error != nil {
-			log.Printf("Error fetching workflows: %v", res.Error)
-			continue
-		}
-		successfulRepos = append(successfulRepos, res.Value.(*Repository))
+		workflows = append(workflows, res.Value.(*Workflow))
 	}
 
-	// Sort repositories by update time (most recent first)
-	sort.Slice(successfulRepos, func(i, j int) bool {
-		return successfulRepos[i].UpdatedAt.After(successfulRepos[j].UpdatedAt)
-	})
+	repository.Workflows = workflows
+	repository.FetchedAt = time.Now()
+	return repository, nil
+}
 
-	return successfulRepos, nil
+// FetchRepositoryInfo fetches a repository without its workflows
+func (c *Client) FetchRepositoryInfo(owner, repo string) (*Repository, error) {
+	requestUrl := fmt.Sprintf("repos/%s/%s", owner, repo)
+	var repository Repository
+	if err := c.Client.Get(requestUrl, &repository); err != nil {
+		return nil, fmt.Errorf("failed to fetch repository %s/%s: %w", owner, repo, err)
+	}
+	return &repository, nil
 }
 
-// FetchWorkflowsWithRuns fetches workflows and their recent runs for a repository
-func (c *Client) FetchWorkflowsWithRuns(owner, repo string) (*Repository, error) {
-	// Fetch repository info first
+// RefreshWorkflow fetches the recent runs of a workflow of a repository given as 'owner/repo',
+// hiding those rejected by the filter, which may be nil.
+// The returned copy of the workflow has its error set when the runs could not be fetched.
+func (c *Client) RefreshWorkflow(fullName string, workflow *Workflow, filter *config.Filter) *Workflow {
+	refreshed := *workflow
+	refreshed.Runs = nil
+	refreshed.Error = nil
+
+	owner, repo := parseFullName(fullName)
+	if owner == "" {
+		refreshed.Error = fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", fullName)
+		return &refreshed
+	}
+	c.fetchWorkflowRuns(owner, repo, &refreshed, filter)
+	return &refreshed
+}
+
+// fetchWorkflowRuns sets the recent runs of the workflow kept by the filter with their jobs, or its error
+func (c *Client) fetchWorkflowRuns(owner, repo string, workflow *Workflow, filter *config.Filter) {
+	runsUrl := fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs?per_page=%d",
+		owner, repo, workflow.ID, workflowRunsPerPage)
+
+	var runsResponse struct {
+		TotalCount   int            `json:"total_count"`
+		WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
+	}
+
+	err := c.Client.Get(runsUrl, &runsResponse)
+	if err != nil {
+		workflow.Error = err
+		return
+	}
+
+	// Fetch jobs for each workflow run, skipping the filtered runs
+	var runs []*WorkflowRun
+	for _, run := range runsResponse.WorkflowRuns {
+		if filter.KeepRun(run.Event, run.Actor.Login) {
+			runs = append(runs, run)
+		}
+	}
+	if len(runs) > 0 {
+		workflow.Runs = c.fetchJobsForRuns(owner, repo, runs)
+	}
+}
+
+// FetchWorkflowsWithLatestRun fetches the workflows of a repository with only their latest run,
+// restricted to a branch when it is not empty. Jobs are not fetched. The workflows and runs
+// rejected by the filter, which may be nil, are skipped.
+func (c *Client) FetchWorkflowsWithLatestRun(owner, repo, branch string, filter *config.Filter) (*Repository, error) {
 	requestUrlRepo := fmt.Sprintf("repos/%s/%s", owner, repo)
 	var repository Repository
 	err := c.Client.Get(requestUrlRepo, &repository)
@@ -117,50 +271,54 @@ func (c *Client) FetchWorkflowsWithRuns(owner, repo string) (*Repository, error)
 		return nil, fmt.Errorf("failed to fetch repository %s/%s: %w", owner, repo, err)
 	}
 
-	// Fetch workflows for the repository
 	requestUrl := fmt.Sprintf("repos/%s/%s/actions/workflows", owner, repo)
 	var workflowsResponse struct {
 		Workflows []Workflow `json:"workflows"`
 	}
-
 	err = c.Client.Get(requestUrl, &workflowsResponse)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch workflows for %s/%s: %w", owner, repo, err)
 	}
 
-	// Convert to interface slice
-	workflowItems := make([]interface{}, len(workflowsResponse.Workflows))
-	for i, workflow := range workflowsResponse.Workflows {
-		workflowItems[i] = workflow
+	var workflowItems []interface{}
+	for _, workflow := range workflowsResponse.Workflows {
+		if filter.KeepWorkflow(workflow.Name, workflow.Path) {
+			workflowItems = append(workflowItems, workflow)
+		}
+	}
+
+	// The latest runs may all be filtered out, a page of runs is fetched to find one to keep
+	perPage := 1
+	if filter.HidesRuns() {
+		perPage = workflowRunsPerPage
 	}
 
 	results := runConcurrent(defaultConcurrency, workflowItems, func(item interface{}) (interface{}, error) {
 		workflow := item.(Workflow)
 
-		// Fetch runs for this workflow
-		runsUrl := fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs?per_page=%d",
-			owner, repo, workflow.ID, workflowRunsPerPage)
+		runsUrl := fmt.Sprintf("repos/%s/%s/actions/workflows/%d/runs?per_page=%d", owner, repo, workflow.ID, perPage)
+		if branch != "" {
+			runsUrl += "&branch=" + url.QueryEscape(branch)
+		}
 
 		var runsResponse struct {
-			TotalCount   int            `json:"total_count"`
 			WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
 		}
-
 		err := c.Client.Get(runsUrl, &runsResponse)
 		if err != nil {
 			workflow.Error = err
-			return &workflow, nil // Return with error set but don't fail
+			return &workflow, nil
 		}
-
-		// Fetch jobs for each workflow run
-		if len(runsResponse.WorkflowRuns) > 0 {
-			workflow.Runs = c.fetchJobsForRuns(owner, repo, runsResponse.WorkflowRuns)
+		for _, run := range runsResponse.WorkflowRuns {
+			if filter.KeepRun(run.Event, run.Actor.Login) {
+				workflow.Runs = []*WorkflowRun{run}
+				break
+			}
 		}
 
 		return &workflow, nil
 	})
 
-	// Process results
 	var workflows []*Workflow
 	for _, res := range results {
 		workflows = append(workflows, res.Value.(*Workflow))
@@ -170,6 +328,113 @@ func (c *Client) FetchWorkflowsWithRuns(owner, repo string) (*Repository, error)
 	return &repository, nil
 }
 
+// FetchRepositoryWithRun fetches a repository with a single workflow holding a single run and all its jobs,
+// those of the latest attempt unless attempt is set
+func (c *Client) FetchRepositoryWithRun(owner, repo string, runID int64, attempt int) (*Repository, error) {
+	requestUrlRepo := fmt.Sprintf("repos/%s/%s", owner, repo)
+	var repository Repository
+	if err := c.Client.Get(requestUrlRepo, &repository); err != nil {
+		return nil, fmt.Errorf("failed to fetch repository %s/%s: %w", owner, repo, err)
+	}
+
+	runUrl := fmt.Sprintf("repos/%s/%s/actions/runs/%d", owner, repo, runID)
+	attemptNumber := ""
+	if attempt > 0 {
+		attemptNumber = strconv.Itoa(attempt)
+		runUrl += "/attempts/" + attemptNumber
+	}
+	var run WorkflowRun
+	if err := c.Client.Get(runUrl, &run); err != nil {
+		return nil, fmt.Errorf("failed to fetch run %d: %w", runID, err)
+	}
+
+	jobs, err := c.FetchJobs(owner, repo, strconv.FormatInt(runID, 10), attemptNumber, "")
+	if err != nil {
+		return nil, err
+	}
+	run.Jobs = jobs
+
+	workflowUrl := fmt.Sprintf("repos/%s/%s/actions/workflows/%d", owner, repo, run.WorkflowID)
+	var workflow Workflow
+	if err := c.Client.Get(workflowUrl, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow %d: %w", run.WorkflowID, err)
+	}
+	workflow.Runs = []*WorkflowRun{&run}
+
+	repository.Workflows = []*Workflow{&workflow}
+	return &repository, nil
+}
+
+// FetchRunsForCommit fetches the latest run of every workflow triggered for a commit.
+// When sha is empty, the latest commit with runs on the branch is used.
+func (c *Client) FetchRunsForCommit(owner, repo, branch, sha string) ([]*WorkflowRun, error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(commitRunsPerPage))
+	if branch != "" {
+		query.Set("branch", branch)
+	}
+	if sha != "" {
+		query.Set("head_sha", sha)
+	}
+
+	requestUrl := fmt.Sprintf("repos/%s/%s/actions/runs?%s", owner, repo, query.Encode())
+	var runsResponse struct {
+		WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
+	}
+	if err := c.Client.Get(requestUrl, &runsResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch runs for %s/%s: %w", owner, repo, err)
+	}
+
+	if sha == "" && len(runsResponse.WorkflowRuns) > 0 {
+		sha = runsResponse.WorkflowRuns[0].HeadSha
+	}
+
+	// Runs are sorted by creation time, keep the most recent one per workflow
+	seen := make(map[int64]bool)
+	var runs []*WorkflowRun
+	for _, run := range runsResponse.WorkflowRuns {
+		if run.HeadSha != sha || seen[run.WorkflowID] {
+			continue
+		}
+		seen[run.WorkflowID] = true
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// FetchRunsWithJobs fetches workflow runs by ID along with every one of their jobs
+func (c *Client) FetchRunsWithJobs(owner, repo string, ids []int64) ([]*WorkflowRun, error) {
+	idItems := make([]interface{}, len(ids))
+	for i, id := range ids {
+		idItems[i] = id
+	}
+
+	results := runConcurrent(defaultConcurrency, idItems, func(item interface{}) (interface{}, error) {
+		requestUrl := fmt.Sprintf("repos/%s/%s/actions/runs/%d", owner, repo, item.(int64))
+		var run WorkflowRun
+		if err := c.Client.Get(requestUrl, &run); err != nil {
+			return nil, fmt.Errorf("failed to fetch run %d: %w", item.(int64), err)
+		}
+		jobs, err := c.FetchJobs(owner, repo, strconv.FormatInt(run.ID, 10), "", "")
+		if err != nil {
+			return nil, err
+		}
+		run.Jobs = jobs
+		return &run, nil
+	})
+
+	runs := make([]*WorkflowRun, len(results))
+	for i, res := range results {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		runs[i] = res.Value.(*WorkflowRun)
+	}
+
+	return runs, nil
+}
+
 // fetchJobsForRuns fetches jobs for a list of workflow runs concurrently
 func (c *Client) fetchJobsForRuns(owner, repo string, runs []*WorkflowRun) []*WorkflowRun {
 	// Convert to interface slice
@@ -207,50 +472,6 @@ func (c *Client) fetchJobsForRuns(owner, repo string, runs []*WorkflowRun) []*Wo
 	return runsWithJobs
 }
 
-// fetchRepositories retrieves repository information for a list of repository names
-func (c *Client) fetchRepositories(names []string) ([]*Repository, error) {
-	if len(names) == 0 {
-		return nil, errors.New("no repository names provided")
-	}
-
-	// Convert to interface slice
-	nameItems := make([]interface{}, len(names))
-	for i, name := range names {
-		nameItems[i] = name
-	}
-
-	results := runConcurrent(defaultConcurrency, nameItems, func(item interface{}) (interface{}, error) {
-		repoName := item.(string)
-
-		repoParts := strings.Split(repoName, "/")
-		if len(repoParts) != 2 {
-			return nil, fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", repoName)
-		}
-
-		requestUrl := fmt.Sprintf("repos/%s/%s", repoParts[0], repoParts[1])
-		var response Repository
-
-		err := c.Client.Get(requestUrl, &response)
-		if err != nil {
-			return nil, err
-		}
-
-		return &response, nil
-	})
-
-	// Process results
-	var repos []*Repository
-	for _, res := range results {
-		if res.Error != nil {
-			log.Printf("Error fetching %v: %v", res.Value, res.Error)
-			continue
-		}
-		repos = append(repos, res.Value.(*Repository))
-	}
-
-	return repos, nil
-}
-
 // parseFullName splits a full repository name into owner and repo parts
 func parseFullName(fullName string) (string, string) {
 	parts := strings.Split(fullName, "/")
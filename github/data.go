@@ -1,6 +1,8 @@
 package github
 
 import (
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -9,27 +11,85 @@ type Repository struct {
 	Name           string      `json:"name"`
 	FullName       string      `json:"full_name"`
 	URL            string      `json:"html_url"`
+	DefaultBranch  string      `json:"default_branch"`
 	UpdatedAt      time.Time   `json:"updated_at"`
 	Language       string      `json:"language"`
 	IsPrivate      bool        `json:"private"`
 	StargazerCount int         `json:"stargazers_count"`
-	Workflows      []*Workflow `json:"-"` // Not directly from the API
-	Error          error       `json:"-"` // Not from the API
+	Topics         []string    `json:"topics"`
+	IsArchived     bool        `json:"archived"`
+	Workflows      []*Workflow `json:"workflows,omitempty"`  // Not directly from the API
+	FetchedAt      time.Time   `json:"fetched_at,omitempty"` // Not from the API
+	Error          error       `json:"-"`                    // Not from the API
+}
+
+// MergeRepositories returns the incoming repositories, reusing the known ones with the same
+// full name, ignoring case, updated in place, so that references held elsewhere see the new data.
+// A known repository that failed to load keeps its data along with the error.
+func MergeRepositories(known, incoming []*Repository) []*Repository {
+	byName := make(map[string]*Repository, len(known))
+	for _, repo := range known {
+		byName[strings.ToLower(repo.FullName)] = repo
+	}
+
+	merged := make([]*Repository, 0, len(incoming))
+	for _, repo := range incoming {
+		if existing, ok := byName[strings.ToLower(repo.FullName)]; ok {
+			existing.update(repo)
+			repo = existing
+		}
+		merged = append(merged, repo)
+	}
+	return merged
+}
+
+// MergeRepository updates the known repository with the same full name in place, or appends
+// the repository to the known ones. The repository held by the returned list is returned.
+func MergeRepository(known []*Repository, repo *Repository) ([]*Repository, *Repository) {
+	if existing := FindRepository(known, repo.FullName); existing != nil {
+		existing.update(repo)
+		return known, existing
+	}
+	return append(known, repo), repo
+}
+
+// update replaces the repository data, or only records the error of a failed load
+func (r *Repository) update(repo *Repository) {
+	if repo.Error != nil && !r.FetchedAt.IsZero() {
+		r.Error = repo.Error
+		return
+	}
+	*r = *repo
+}
+
+// FindRepository returns the repository with the given full name, ignoring case
+func FindRepository(repos []*Repository, fullName string) *Repository {
+	for _, repo := range repos {
+		if strings.EqualFold(repo.FullName, fullName) {
+			return repo
+		}
+	}
+	return nil
 }
 
 // Workflow represents a GitHub Actions workflow
 type Workflow struct {
 	ID    int64          `json:"id"`
 	Name  string         `json:"name"`
+	Path  string         `json:"path"`
 	State string         `json:"state"`
 	URL   string         `json:"html_url"`
-	Runs  []*WorkflowRun `json:"-"` // Not from direct API response
-	Error error          `json:"-"` // Not from API
+	Runs  []*WorkflowRun `json:"runs,omitempty"` // Not from direct API response
+	Error error          `json:"-"`              // Not from API
 }
 
 // WorkflowRun represents a run of a GitHub Actions workflow
 type WorkflowRun struct {
 	ID           int64     `json:"id"`
+	WorkflowID   int64     `json:"workflow_id"`
+	Name         string    `json:"name"`
+	RunAttempt   int       `json:"run_attempt"`
+	HeadSha      string    `json:"head_sha"`
 	Status       string    `json:"status"`
 	Conclusion   string    `json:"conclusion"`
 	CreatedAt    time.Time `json:"created_at"`
@@ -39,7 +99,13 @@ type WorkflowRun struct {
 	URL          string    `json:"html_url"`
 	HeadBranch   string    `json:"head_branch"`
 	HeadCommit   Commit    `json:"head_commit"`
-	Jobs         []*Job    `json:"-"` // Fetched separately
+	Actor        User      `json:"actor"`
+	Jobs         []*Job    `json:"jobs,omitempty"` // Fetched separately
+}
+
+// User is the account that triggered a run
+type User struct {
+	Login string `json:"login"`
 }
 
 // Commit represents a git commit
@@ -51,6 +117,8 @@ type Commit struct {
 // Job represents a job in a workflow run
 type Job struct {
 	ID          int64     `json:"id"`
+	RunID       int64     `json:"run_id"`
+	RunAttempt  int       `json:"run_attempt"`
 	Name        string    `json:"name"`
 	Status      string    `json:"status"`
 	Conclusion  string    `json:"conclusion"`
@@ -84,6 +152,33 @@ func (r Repository) GetURL() string {
 	return r.URL
 }
 
+func (w Workflow) GetName() string {
+	return w.Name
+}
+
+func (w Workflow) GetURL() string {
+	return w.URL
+}
+
+// IsFailure reports whether the run completed with a failing conclusion
+func (w WorkflowRun) IsFailure() bool {
+	return w.Status == "completed" && IsFailureConclusion(w.Conclusion)
+}
+
+// Attempt returns the run attempt as used in API paths
+func (w WorkflowRun) Attempt() string {
+	return strconv.Itoa(max(w.RunAttempt, 1))
+}
+
+// IsFailureConclusion reports whether a run, job or step conclusion is a failure
+func IsFailureConclusion(conclusion string) bool {
+	switch conclusion {
+	case "failure", "timed_out", "startup_failure":
+		return true
+	}
+	return false
+}
+
 func (w WorkflowRun) GetName() string {
 	return w.DisplayTitle
 }
@@ -92,6 +187,11 @@ func (w WorkflowRun) GetURL() string {
 	return w.URL
 }
 
+// Attempt returns the attempt of the run the job belongs to as used in API paths
+func (j Job) Attempt() string {
+	return strconv.Itoa(max(j.RunAttempt, 1))
+}
+
 func (j Job) GetName() string {
 	return j.Name
 }
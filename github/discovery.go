@@ -0,0 +1,292 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/cpaluszek/gh-ci/cache"
+	"github.com/cpaluszek/gh-ci/config"
+)
+
+const (
+	discoveryCacheTTL = 6 * time.Hour
+	searchPerPage     = 100
+	maxSearchResults  = 1000
+	// maxAccessibleRepositories bounds the repositories listed by FetchAccessibleRepositories
+	maxAccessibleRepositories = 300
+)
+
+// ErrIncompleteDiscovery is wrapped by the error ResolveRepositories returns along with the
+// repositories it resolved when some entries or repositories could not be checked
+var ErrIncompleteDiscovery = errors.New("some repositories could not be discovered")
+
+type searchRepositoriesResponse struct {
+	TotalCount int           `json:"total_count"`
+	Items      []*Repository `json:"items"`
+}
+
+// ResolveRepositories expands the discovery entries of the config into 'owner/repo' names.
+// Explicit repositories are kept in config order, discovered ones are filtered by topics and
+// only kept when they have workflows. Excluded repositories are removed from both.
+// When a discovery fails, the repositories resolved anyway are returned, uncached, with an
+// error wrapping ErrIncompleteDiscovery.
+func (c *Client) ResolveRepositories(cfg config.GithubConfig) ([]string, error) {
+	var explicit []string
+	var discovery []config.Entry
+	for _, name := range cfg.Entries() {
+		entry, err := config.ParseEntry(name)
+		if err != nil {
+			return nil, err
+		}
+		if entry.IsDiscovery() {
+			discovery = append(discovery, entry)
+		} else {
+			explicit = append(explicit, name)
+		}
+	}
+
+	if len(discovery) == 0 {
+		return dedupeRepositories(filterExcluded(explicit, cfg.Exclude)), nil
+	}
+
+	cacheKey := configCacheKey("repositories", cfg)
+	repoCache, err := cache.Shared()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache: %w", err)
+	}
+	if names, found := cache.Get[[]string](repoCache, cacheKey); found {
+		return names, nil
+	}
+
+	var discovered []*Repository
+	var errs []error
+	for _, entry := range discovery {
+		repos, err := c.discoverRepositories(entry)
+		if err != nil {
+			if IsNetworkError(err) {
+				return nil, err
+			}
+			log.Printf("Error discovering repositories: %v", err)
+			errs = append(errs, err)
+			continue
+		}
+		discovered = append(discovered, repos...)
+	}
+
+	var candidates []string
+	for _, repo := range discovered {
+		if repo.IsArchived || !hasAnyTopic(repo, cfg.Topics) {
+			continue
+		}
+		candidates = append(candidates, repo.FullName)
+	}
+	candidates = dedupeRepositories(filterExcluded(candidates, cfg.Exclude))
+
+	withWorkflows, err := c.FilterWithWorkflows(candidates)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	names := dedupeRepositories(append(filterExcluded(explicit, cfg.Exclude), withWorkflows...))
+	// An incomplete list would hide the missing repositories until the cache expires
+	if len(errs) > 0 {
+		return names, fmt.Errorf("%w: %w", ErrIncompleteDiscovery, errors.Join(errs...))
+	}
+	if len(names) > 0 {
+		if err := cache.Set(repoCache, cacheKey, names, discoveryCacheTTL); err != nil {
+			log.Printf("Warning: failed to cache resolved repositories: %v", err)
+		}
+	}
+
+	return names, nil
+}
+
+// configCacheKey returns a cache key identifying the repositories selected by the config
+func configCacheKey(kind string, cfg config.GithubConfig) string {
+	return fmt.Sprintf("%s:%s:%s|%s|%s", kind, cfg.CacheScope(),
+		strings.Join(cfg.Entries(), ","), strings.Join(cfg.Topics, ","), strings.Join(cfg.Exclude, ","))
+}
+
+// discoverRepositories lists the repositories matching an organization, user or pattern entry
+func (c *Client) discoverRepositories(entry config.Entry) ([]*Repository, error) {
+	var query string
+	switch entry.Kind {
+	case config.OrganizationEntry:
+		query = "org:" + entry.Owner
+	case config.UserEntry, config.PatternEntry:
+		// The user qualifier matches organizations too
+		query = "user:" + entry.Owner
+	default:
+		return nil, fmt.Errorf("entry %s/%s is not a discovery entry", entry.Owner, entry.Name)
+	}
+
+	repos, err := c.searchRepositories(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Kind != config.PatternEntry {
+		return repos, nil
+	}
+
+	pattern := entry.Owner + "/" + entry.Name
+	var matching []*Repository
+	for _, repo := range repos {
+		if config.MatchPattern(pattern, repo.FullName) {
+			matching = append(matching, repo)
+		}
+	}
+	return matching, nil
+}
+
+// searchRepositories pages through the repository search API for the given query
+func (c *Client) searchRepositories(query string) ([]*Repository, error) {
+	var repos []*Repository
+	for page := 1; len(repos) < maxSearchResults; page++ {
+		requestUrl := fmt.Sprintf("search/repositories?q=%s&per_page=%d&page=%d",
+			url.QueryEscape(query), searchPerPage, page)
+
+		var response searchRepositoriesResponse
+		if err := c.Client.Get(requestUrl, &response); err != nil {
+			return nil, fmt.Errorf("failed to search repositories for %s: %w", query, err)
+		}
+
+		repos = append(repos, response.Items...)
+		if len(response.Items) < searchPerPage || len(repos) >= response.TotalCount {
+			break
+		}
+	}
+	return repos, nil
+}
+
+// FetchAccessibleRepositories lists the repositories the authenticated user owns, collaborates on
+// or reaches through an organization, most recently pushed first. Archived repositories are left out.
+func (c *Client) FetchAccessibleRepositories() ([]*Repository, error) {
+	cacheKey, err := c.accessibleCacheKey()
+	if err != nil {
+		return nil, err
+	}
+	repoCache, err := cache.Shared()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache: %w", err)
+	}
+	if repos, found := cache.Get[[]*Repository](repoCache, cacheKey); found {
+		return repos, nil
+	}
+
+	var repos []*Repository
+	for page := 1; len(repos) < maxAccessibleRepositories; page++ {
+		requestUrl := fmt.Sprintf("user/repos?affiliation=owner,collaborator,organization_member&sort=pushed&per_page=%d&page=%d",
+			searchPerPage, page)
+
+		var response []*Repository
+		if err := c.Client.Get(requestUrl, &response); err != nil {
+			return nil, fmt.Errorf("failed to list accessible repositories: %w", err)
+		}
+
+		for _, repo := range response {
+			if !repo.IsArchived {
+				repos = append(repos, repo)
+			}
+		}
+		if len(response) < searchPerPage {
+			break
+		}
+	}
+
+	if err := cache.Set(repoCache, cacheKey, repos, discoveryCacheTTL); err != nil {
+		log.Printf("Warning: failed to cache accessible repositories: %v", err)
+	}
+	return repos, nil
+}
+
+// accessibleCacheKey scopes the accessible repositories to the host and the account of the client,
+// so that they are not shown after switching accounts
+func (c *Client) accessibleCacheKey() (string, error) {
+	host, _ := auth.DefaultHost()
+	var user User
+	if err := c.Client.Get("user", &user); err != nil {
+		return "", fmt.Errorf("failed to fetch the authenticated user: %w", err)
+	}
+	return fmt.Sprintf("accessible:%s:%s", host, user.Login), nil
+}
+
+// FilterWithWorkflows keeps the repositories that define at least one workflow. The repositories
+// that could not be checked are left out and reported by the error.
+func (c *Client) FilterWithWorkflows(names []string) ([]string, error) {
+	nameItems := make([]interface{}, len(names))
+	for i, name := range names {
+		nameItems[i] = name
+	}
+
+	results := runConcurrent(defaultConcurrency, nameItems, func(item interface{}) (interface{}, error) {
+		owner, repo := parseFullName(item.(string))
+		requestUrl := fmt.Sprintf("repos/%s/%s/actions/workflows?per_page=1", owner, repo)
+
+		var response struct {
+			TotalCount int `json:"total_count"`
+		}
+		if err := c.Client.Get(requestUrl, &response); err != nil {
+			return false, err
+		}
+		return response.TotalCount > 0, nil
+	})
+
+	var withWorkflows []string
+	var errs []error
+	for i, res := range results {
+		if res.Error != nil {
+			log.Printf("Error fetching workflows for %s: %v", names[i], res.Error)
+			errs = append(errs, fmt.Errorf("failed to check the workflows of %s: %w", names[i], res.Error))
+			continue
+		}
+		if res.Value.(bool) {
+			withWorkflows = append(withWorkflows, names[i])
+		}
+	}
+	return withWorkflows, errors.Join(errs...)
+}
+
+func hasAnyTopic(repo *Repository, topics []string) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, topic := range topics {
+		if slices.Contains(repo.Topics, strings.ToLower(topic)) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterExcluded(names []string, exclude []string) []string {
+	var kept []string
+	for _, name := range names {
+		excluded := slices.ContainsFunc(exclude, func(pattern string) bool {
+			return config.MatchPattern(pattern, name)
+		})
+		if !excluded {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+func dedupeRepositories(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// ErrorStatus returns the HTTP status code and message of an API error,
+// the status code is zero when err does not come from an API response
+func ErrorStatus(err error) (int, string) {
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) {
+		return 0, ""
+	}
+	message := httpErr.Message
+	if message == "" {
+		message = http.StatusText(httpErr.StatusCode)
+	}
+	return httpErr.StatusCode, message
+}
+
+// ShortError describes err in a few words, to fit in a table cell
+func ShortError(err error) string {
+	if status, _ := ErrorStatus(err); status != 0 {
+		return fmt.Sprintf("HTTP %d", status)
+	}
+	if IsNetworkError(err) {
+		return "unreachable"
+	}
+	return "error"
+}
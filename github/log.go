@@ -38,7 +38,21 @@ type GitHubRunInfo struct {
 	User  string `json:"user"`
 	Repo  string `json:"repo"`
 	RunID string `json:"run_id"`
-	JobID string `json:"job_id,omitempty"`
+	// Attempt is set for URLs of a specific attempt of the run, e.g. '.../runs/123/attempts/2'
+	Attempt string `json:"attempt,omitempty"`
+	JobID   string `json:"job_id,omitempty"`
+}
+
+// LogAttempt returns the attempt of the run whose logs belong to the job: the attempt of the URL
+// when it names one, otherwise the attempt of the job, the first one by default
+func (i GitHubRunInfo) LogAttempt(job *Job) string {
+	if i.Attempt != "" {
+		return i.Attempt
+	}
+	if job != nil && job.RunAttempt > 0 {
+		return job.Attempt()
+	}
+	return "1"
 }
 
 type GitHubJobsResponse struct {
@@ -58,7 +72,7 @@ func ParseGitHubURL(rawURL string) (*GitHubRunInfo, error) {
 	if u.Host != "github.com" {
 		return nil, fmt.Errorf("URL must be from github.com")
 	}
-	pathRegex := regexp.MustCompile(`^/([^/]+)/([^/]+)/actions/runs/(\d+)(?:/job/(\d+))?`)
+	pathRegex := regexp.MustCompile(`^/([^/]+)/([^/]+)/actions/runs/(\d+)(?:/attempts/(\d+))?(?:/job/(\d+))?`)
 	matches := pathRegex.FindStringSubmatch(u.Path)
 	if len(matches) < 4 {
 		return nil, fmt.Errorf("invalid GitHub Actions URL format")
@@ -69,26 +83,27 @@ func ParseGitHubURL(rawURL string) (*GitHubRunInfo, error) {
 		RunID: matches[3],
 	}
 	if len(matches) > 4 && matches[4] != "" {
-		info.JobID = matches[4]
+		info.Attempt = matches[4]
+	}
+	if len(matches) > 5 && matches[5] != "" {
+		info.JobID = matches[5]
 	}
 	return info, nil
 }
 
 func (c *Client) GetLogs(user, repo, runID, attempt string, jobName string) ([]Steplog, error) {
-	cacheKey := fmt.Sprintf("logs:%s:%s:%s:%s:%s", user, repo, runID, attempt, jobName)
-	cache, err := cache.LoadCache()
+	cacheKey := fmt.Sprintf("logs:%s/%s:%s:%s:%s", user, repo, runID, attempt, jobName)
+	logCache, err := cache.Shared()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load cache: %v", err)
 	}
 
-	if cachedData, found := cache.Get(cacheKey); found {
-		if steplogs, ok := cachedData.([]Steplog); ok {
-			return steplogs, nil
-		}
+	if steplogs, found := cache.Get[[]Steplog](logCache, cacheKey); found {
+		return steplogs, nil
 	}
 
-	zipCacheKey := fmt.Sprintf("logs:%s:%s:%s:%s", user, repo, runID, attempt)
-	zipPath, foundFile := cache.GetFileCache(zipCacheKey)
+	zipCacheKey := fmt.Sprintf("logs:%s/%s:%s:%s", user, repo, runID, attempt)
+	zipPath, foundFile := logCache.GetFileCache(zipCacheKey)
 	var zipData []byte
 
 	if !foundFile {
@@ -114,16 +129,10 @@ func (c *Client) GetLogs(user, repo, runID, attempt string, jobName string) ([]S
 		log.Printf("Fetched logs successfully, size: %d bytes\n", len(zipData))
 
 		// TODO: use .config file log TTL
-		zipPath, err = cache.SetFileCache(zipCacheKey, zipData, time.Hour)
+		zipPath, err = logCache.SetFileCache(zipCacheKey, zipData, time.Hour)
 		if err != nil {
 			return nil, fmt.Errorf("failed to cache zip file: %v", err)
 		}
-		defer func() {
-			removeErr := os.Remove(zipPath)
-			if removeErr == nil {
-				err = removeErr
-			}
-		}()
 	} else {
 		log.Printf("Using cached zip file: %s\n", zipPath)
 		zipData, err = os.ReadFile(zipPath)
@@ -133,7 +142,7 @@ func (c *Client) GetLogs(user, repo, runID, attempt string, jobName string) ([]S
 	}
 
 	// fetch metadata for steps
-	stepMeta, err := c.FetchGitHubJobSteps(user, repo, runID, jobName)
+	stepMeta, err := c.FetchGitHubJobSteps(user, repo, runID, attempt, jobName)
 	if err != nil {
 		return nil, err
 	}
@@ -143,26 +152,23 @@ func (c *Client) GetLogs(user, repo, runID, attempt string, jobName string) ([]S
 		return nil, err
 	}
 
-	if err := cache.Set(cacheKey, steplogs, 30*time.Minute); err != nil {
-		fmt.Printf("Warning: failed to cache parsed logs: %v\n", err)
+	if err := cache.Set(logCache, cacheKey, steplogs, 30*time.Minute); err != nil {
+		log.Printf("Warning: failed to cache parsed logs: %v\n", err)
 	}
 
 	return steplogs, nil
 }
 
-func (c *Client) FetchGitHubJobSteps(owner, repo, runID, jobname string) (map[int]Step, error) {
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/runs/%s/jobs", owner, repo, runID)
-
-	var jobsResponse GitHubJobsResponse
-
-	err := c.Client.Get(apiURL, &jobsResponse)
+// FetchGitHubJobSteps returns the steps of a job of an attempt of a run by number
+func (c *Client) FetchGitHubJobSteps(owner, repo, runID, attempt, jobname string) (map[int]Step, error) {
+	jobs, err := c.FetchJobs(owner, repo, runID, attempt, "")
 	if err != nil {
 		return nil, err
 	}
 
 	stepMap := make(map[int]Step)
 
-	for _, job := range jobsResponse.Jobs {
+	for _, job := range jobs {
 		if job.Name == jobname {
 			for _, step := range job.Steps {
 				stepMap[step.Number] = step
@@ -174,6 +180,41 @@ func (c *Client) FetchGitHubJobSteps(owner, repo, runID, jobname string) (map[in
 	return stepMap, nil
 }
 
+// FetchJobs fetches every job of a workflow run, of its latest attempt unless attempt is set,
+// or a single job when jobID is not empty
+func (c *Client) FetchJobs(owner, repo, runID, attempt, jobID string) ([]*Job, error) {
+	if jobID != "" {
+		var job Job
+		apiURL := fmt.Sprintf("repos/%s/%s/actions/jobs/%s", owner, repo, jobID)
+		if err := c.Client.Get(apiURL, &job); err != nil {
+			return nil, fmt.Errorf("failed to fetch job %s: %w", jobID, err)
+		}
+		return []*Job{&job}, nil
+	}
+
+	jobsURL := fmt.Sprintf("repos/%s/%s/actions/runs/%s/jobs", owner, repo, runID)
+	if attempt != "" {
+		jobsURL = fmt.Sprintf("repos/%s/%s/actions/runs/%s/attempts/%s/jobs", owner, repo, runID, attempt)
+	}
+
+	var jobs []*Job
+	for page := 1; ; page++ {
+		var jobsResponse struct {
+			TotalCount int    `json:"total_count"`
+			Jobs       []*Job `json:"jobs"`
+		}
+		apiURL := fmt.Sprintf("%s?per_page=%d&page=%d", jobsURL, jobsPageSize, page)
+		if err := c.Client.Get(apiURL, &jobsResponse); err != nil {
+			return nil, fmt.Errorf("failed to fetch jobs of run %s: %w", runID, err)
+		}
+
+		jobs = append(jobs, jobsResponse.Jobs...)
+		if len(jobsResponse.Jobs) < jobsPageSize || len(jobs) >= jobsResponse.TotalCount {
+			return jobs, nil
+		}
+	}
+}
+
 func ParseZipLogs(zipData []byte, stepMeta map[int]Step, jobName string) ([]Steplog, error) {
 	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
 	if err != nil {
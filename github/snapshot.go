@@ -0,0 +1,40 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cpaluszek/gh-ci/cache"
+	"github.com/cpaluszek/gh-ci/config"
+)
+
+// snapshotTTL is how long the last fetched repositories can be browsed offline
+const snapshotTTL = 30 * 24 * time.Hour
+
+// IsNetworkError reports whether err comes from a failure to reach GitHub rather than from the API
+func IsNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// SaveSnapshot persists the repositories with their workflows, runs and jobs,
+// so that they can be browsed when GitHub cannot be reached
+func SaveSnapshot(cfg config.GithubConfig, repos []*Repository) error {
+	c, err := cache.Shared()
+	if err != nil {
+		return fmt.Errorf("failed to load cache: %w", err)
+	}
+	return cache.Set(c, configCacheKey("snapshot", cfg), repos, snapshotTTL)
+}
+
+// LoadSnapshot returns the repositories saved by the last SaveSnapshot of the same config
+func LoadSnapshot(cfg config.GithubConfig) ([]*Repository, bool) {
+	c, err := cache.Shared()
+	if err != nil {
+		return nil, false
+	}
+	repos, found := cache.Get[[]*Repository](c, configCacheKey("snapshot", cfg))
+	return repos, found && len(repos) > 0
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const appName = "gh-ci"
+
+// Desktop shows notifications through the freedesktop notification service, with notify-send
+// or, when it is missing, a D-Bus call made by gdbus
+type Desktop struct {
+	notifySend string
+	gdbus      string
+}
+
+// NewDesktop returns the desktop backend, nil when neither notify-send nor gdbus is installed
+func NewDesktop() *Desktop {
+	if path, err := exec.LookPath("notify-send"); err == nil {
+		return &Desktop{notifySend: path}
+	}
+	if path, err := exec.LookPath("gdbus"); err == nil {
+		return &Desktop{gdbus: path}
+	}
+	return nil
+}
+
+func (d *Desktop) Notify(notification Notification) error {
+	var cmd *exec.Cmd
+	if d.notifySend != "" {
+		cmd = exec.Command(d.notifySend, "--app-name="+appName, notification.Title, notification.Body)
+	} else {
+		cmd = exec.Command(d.gdbus, "call", "--session",
+			"--dest", "org.freedesktop.Notifications",
+			"--object-path", "/org/freedesktop/Notifications",
+			"--method", "org.freedesktop.Notifications.Notify",
+			appName, "0", "''", gvariantString(notification.Title), gvariantString(notification.Body),
+			"[]", "{}", "-1")
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// gvariantString quotes a string in the text format of GVariant parsed by gdbus
+func gvariantString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
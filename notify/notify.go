@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
+)
+
+// Notification tells the user about a run, outside of the TUI
+type Notification struct {
+	Title string
+	Body  string
+	URL   string
+}
+
+// Backend delivers notifications
+type Backend interface {
+	Notify(notification Notification) error
+}
+
+// New returns the backend of the config, nil for the none backend.
+// The auto backend uses the desktop when it is available and falls back to the terminal.
+func New(cfg config.NotificationsConfig) Backend {
+	switch cfg.BackendName() {
+	case config.BackendNone:
+		return nil
+	case config.BackendTerminal:
+		return NewTerminal()
+	case config.BackendDesktop:
+		if desktop := NewDesktop(); desktop != nil {
+			return desktop
+		}
+		return unavailable{}
+	}
+	if desktop := NewDesktop(); desktop != nil {
+		return fallback{desktop, NewTerminal()}
+	}
+	return NewTerminal()
+}
+
+// NewWithoutTerminal returns the backend of the config for programs drawing on the alternate screen,
+// where the sequences of the terminal backend would corrupt the frame. The terminal backend is left
+// out, inApp reports that the program has to show the notifications itself instead.
+func NewWithoutTerminal(cfg config.NotificationsConfig) (backend Backend, inApp bool) {
+	switch cfg.BackendName() {
+	case config.BackendTerminal:
+		return nil, true
+	case config.BackendAuto:
+		if desktop := NewDesktop(); desktop != nil {
+			return desktop, false
+		}
+		return nil, true
+	}
+	return New(cfg), false
+}
+
+// Send delivers the notifications, a nil backend drops them
+func Send(backend Backend, notifications ...Notification) error {
+	if backend == nil {
+		return nil
+	}
+	var errs []error
+	for _, notification := range notifications {
+		if err := backend.Notify(notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunCompleted describes a completed run of a repository
+func RunCompleted(fullName string, run *github.WorkflowRun) Notification {
+	body := fmt.Sprintf("%s on %s", describeConclusion(run.Conclusion), run.HeadBranch)
+	if title, _, _ := strings.Cut(run.DisplayTitle, "\n"); title != "" {
+		body += ": " + title
+	}
+	return Notification{
+		Title: fmt.Sprintf("%s › %s", fullName, run.Name),
+		Body:  body,
+		URL:   run.URL,
+	}
+}
+
+func describeConclusion(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return "Succeeded"
+	case "failure":
+		return "Failed"
+	case "cancelled":
+		return "Cancelled"
+	case "timed_out":
+		return "Timed out"
+	case "startup_failure":
+		return "Failed to start"
+	case "":
+		return "Completed"
+	}
+	return "Completed (" + strings.ReplaceAll(conclusion, "_", " ") + ")"
+}
+
+// fallback tries its backends in order until one delivers the notification
+type fallback []Backend
+
+func (f fallback) Notify(notification Notification) error {
+	var errs []error
+	for _, backend := range f {
+		err := backend.Notify(notification)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// unavailable reports that the desktop backend was requested but cannot be used
+type unavailable struct{}
+
+func (unavailable) Notify(Notification) error {
+	return errors.New("desktop notifications need notify-send or gdbus")
+}
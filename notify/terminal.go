@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Terminal rings the bell and sends an OSC 9 notification, shown by terminals such as
+// iTerm2, WezTerm, kitty or Windows Terminal
+type Terminal struct {
+	out io.Writer
+}
+
+// NewTerminal writes to stderr, which reaches the terminal without going through the TUI rendering
+func NewTerminal() *Terminal {
+	return &Terminal{out: os.Stderr}
+}
+
+func (t *Terminal) Notify(notification Notification) error {
+	message := sanitize(notification.Title + ": " + notification.Body)
+	// A single write keeps the sequence whole next to the output of the TUI
+	if _, err := fmt.Fprintf(t.out, "\x1b]9;%s\x07\a", message); err != nil {
+		return fmt.Errorf("terminal notification failed: %w", err)
+	}
+	return nil
+}
+
+// sanitize drops the control characters that would end the escape sequence early
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
+)
+
+// Tracker follows the runs of repositories fetched again and again, and reports those that
+// completed since the previous fetch when they match the rules of the config
+type Tracker struct {
+	cfg          config.NotificationsConfig
+	repositories map[string]*trackedRepository
+}
+
+type trackedRepository struct {
+	statuses   map[int64]string
+	observedAt time.Time
+}
+
+func NewTracker(cfg config.NotificationsConfig) *Tracker {
+	return &Tracker{
+		cfg:          cfg,
+		repositories: make(map[string]*trackedRepository),
+	}
+}
+
+// SetConfig replaces the rules, the known runs are kept
+func (t *Tracker) SetConfig(cfg config.NotificationsConfig) {
+	t.cfg = cfg
+}
+
+// Observe records the runs of a freshly fetched repository and returns the notifications of the
+// runs that completed since it was last observed. The first observation of a repository only
+// records its runs.
+func (t *Tracker) Observe(repo *github.Repository) []Notification {
+	if repo == nil || repo.Error != nil {
+		return nil
+	}
+
+	key := strings.ToLower(repo.FullName)
+	previous, known := t.repositories[key]
+	current := &trackedRepository{
+		statuses:   make(map[int64]string),
+		observedAt: time.Now(),
+	}
+	t.repositories[key] = current
+
+	var notifications []Notification
+	for _, workflow := range repo.Workflows {
+		for _, run := range workflow.Runs {
+			current.statuses[run.ID] = run.Status
+			if !known || run.Status != "completed" {
+				continue
+			}
+			status, seen := previous.statuses[run.ID]
+			// Runs seen for the first time, e.g. once hidden workflows are shown, only count when recent
+			if (seen && status == "completed") || (!seen && run.UpdatedAt.Before(previous.observedAt)) {
+				continue
+			}
+			if t.cfg.Notifies(repo.FullName, repo.DefaultBranch, run.HeadBranch, run.Conclusion) {
+				notifications = append(notifications, RunCompleted(repo.FullName, run))
+			}
+		}
+	}
+	return notifications
+}
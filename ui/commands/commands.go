@@ -1,14 +1,20 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cpaluszek/gh-ci/cache"
 	"github.com/cpaluszek/gh-ci/config"
 	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/notify"
 )
 
 type ClientInitMsg struct {
@@ -21,8 +27,68 @@ type ConfigInitMsg struct {
 
 type RepositoriesMsg struct {
 	Repositories []*github.Repository
+	// Offline is set when the repositories come from the snapshot of a previous session
+	Offline bool
+	// Cached is set when the repositories come from the snapshot while live data is fetched
+	Cached bool
 }
 
+// RepositoriesLoadingMsg lists the repositories of a stream before they are fetched
+type RepositoriesLoadingMsg struct {
+	Names []string
+	// Repositories holds the known repositories of the names, or placeholders for the new ones
+	Repositories []*github.Repository
+	// Next waits for the first repository of the stream
+	Next tea.Cmd
+}
+
+// RepositoryMsg delivers a repository of a stream, its Error is set when it could not be fetched
+type RepositoryMsg struct {
+	// Name is the requested 'owner/repo', the case may differ from the repository full name
+	Name       string
+	Repository *github.Repository
+	// Next waits for the next repository of the stream
+	Next tea.Cmd
+	// Retry is set when the repository was fetched again on request
+	Retry bool
+}
+
+// WorkflowRefreshedMsg delivers a workflow of the repository fetched again,
+// its Error is set when its runs could not be fetched
+type WorkflowRefreshedMsg struct {
+	Repository *github.Repository
+	Workflow   *github.Workflow
+}
+
+// ConfigChangedMsg reports an edit of the config file, Error is set when the new config is invalid
+type ConfigChangedMsg struct {
+	Config *config.Config
+	Error  error
+	// Toast confirms an edit made from the UI, it replaces the reload notification
+	Toast string
+	// Next waits for the following change
+	Next tea.Cmd
+}
+
+// RepositoriesReloadedMsg lists the repositories added to and removed from the config.
+// The added repositories are then delivered as RepositoryMsg.
+type RepositoriesReloadedMsg struct {
+	Added   []string
+	Removed []string
+	// Toast is the Toast of the ConfigChangedMsg that triggered the reload
+	Toast string
+	// Next waits for the first added repository
+	Next tea.Cmd
+}
+
+// OfflineMsg reports that GitHub could not be reached and no snapshot replaced the live data
+type OfflineMsg struct {
+	Error error
+}
+
+// ReconnectMsg triggers a new attempt to fetch live data while offline
+type ReconnectMsg struct{}
+
 type WorkflowsMsg struct {
 	Workflows *github.Repository
 }
@@ -41,17 +107,67 @@ type GotostepMsg struct {
 
 type SectionChangedMsg struct{}
 
+// JumpToMsg requests navigation to a repository and optionally one of its runs and jobs
+type JumpToMsg struct {
+	Repository *github.Repository
+	Run        *github.WorkflowRun
+	Job        *github.Job
+}
+
 type ErrorMsg struct {
 	Error error
+	// Request describes what was being done, shown in the error log
+	Request string
+}
+
+// DiscoveryErrorMsg reports repositories of the config that could not be discovered,
+// the repositories resolved anyway are loaded
+type DiscoveryErrorMsg struct {
+	Error error
+}
+
+// withDiscoveryError delivers msg along with a DiscoveryErrorMsg when the discovery was incomplete
+func withDiscoveryError(err error, msg tea.Msg) tea.Msg {
+	if err == nil {
+		return msg
+	}
+	return tea.BatchMsg{
+		func() tea.Msg { return DiscoveryErrorMsg{Error: err} },
+		func() tea.Msg { return msg },
+	}
+}
+
+// ToastLevel is the severity of a toast, it sets its color and how long it stays
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// ToastMsg shows a transient notification
+type ToastMsg struct {
+	Message string
+	Level   ToastLevel
 }
 
+// reconnectInterval is the delay between attempts to fetch live data while offline
+const reconnectInterval = 30 * time.Second
+
 // Commands
-func InitClient() tea.Cmd {
+func InitClient(cfg config.GithubConfig) tea.Cmd {
 	return func() tea.Msg {
 		client, err := github.NewClient()
 		if err != nil {
+			if repos, found := github.LoadSnapshot(cfg); found {
+				log.Printf("Failed to create client, using the last snapshot: %v", err)
+				return RepositoriesMsg{Repositories: repos, Offline: true}
+			}
 			return ErrorMsg{
-				Error: err,
+				Error:   err,
+				Request: "create the GitHub client",
 			}
 		}
 		return ClientInitMsg{
@@ -60,18 +176,384 @@ func InitClient() tea.Cmd {
 	}
 }
 
+// PruneCache sweeps the expired and least recently used cache entries in the background
+func PruneCache() tea.Cmd {
+	return func() tea.Msg {
+		c, err := cache.Shared()
+		if err != nil {
+			log.Printf("failed to load cache: %v", err)
+			return nil
+		}
+		result, err := c.Prune()
+		if err != nil {
+			log.Printf("failed to prune cache: %v", err)
+			return nil
+		}
+		log.Printf("Pruned cache: %d entries, %d files, %d bytes", result.Entries, result.Files, result.Freed)
+		return nil
+	}
+}
+
 func SectionChanged() tea.Msg {
 	return SectionChangedMsg{}
 }
 
-func FetchRepositories(client *github.Client, names []string) tea.Cmd {
+// Toast shows a transient notification
+func Toast(level ToastLevel, message string) tea.Cmd {
+	return func() tea.Msg {
+		return ToastMsg{Message: message, Level: level}
+	}
+}
+
+// StreamRepositories fetches the repositories of the config and delivers them one by one:
+// a RepositoriesLoadingMsg, a RepositoryMsg per repository and a final RepositoriesMsg.
+// The snapshot of a previous session is used when GitHub cannot be reached.
+func StreamRepositories(client *github.Client, cfg config.GithubConfig) tea.Cmd {
+	return func() tea.Msg {
+		names, err := client.ResolveRepositories(cfg)
+		if err != nil && !errors.Is(err, github.ErrIncompleteDiscovery) {
+			if github.IsNetworkError(err) {
+				return offlineFallback(cfg, err)
+			}
+			return ErrorMsg{Error: err, Request: "resolve the repositories of the config"}
+		}
+		if len(names) == 0 {
+			return withDiscoveryError(err, RepositoriesMsg{})
+		}
+
+		stream := &repositoryStream{
+			cfg:     cfg,
+			results: client.StreamRepositoriesWithWorkflows(names, cfg),
+		}
+		return withDiscoveryError(err, RepositoriesLoadingMsg{
+			Names: names,
+			Next:  stream.next,
+		})
+	}
+}
+
+// repositoryStream collects the repositories of a StreamRepositories
+type repositoryStream struct {
+	cfg        config.GithubConfig
+	results    <-chan github.RepositoryResult
+	repos      []*github.Repository
+	fetched    int
+	networkErr error
+	// partial streams only fetch some repositories of the config, they end without a RepositoriesMsg
+	partial bool
+}
+
+// next waits for the next repository of the stream
+func (s *repositoryStream) next() tea.Msg {
+	result, ok := <-s.results
+	if !ok {
+		return s.done()
+	}
+
+	// Failed repositories stay listed with their error
+	if result.Error != nil {
+		log.Printf("Error fetching %s: %v", result.Name, result.Error)
+		if github.IsNetworkError(result.Error) {
+			s.networkErr = result.Error
+		}
+	} else {
+		s.fetched++
+	}
+	s.repos = append(s.repos, result.Repository)
+
+	return RepositoryMsg{
+		Name:       result.Name,
+		Repository: result.Repository,
+		Next:       s.next,
+	}
+}
+
+func (s *repositoryStream) done() tea.Msg {
+	if s.partial {
+		return nil
+	}
+	if s.fetched == 0 && s.networkErr != nil {
+		return offlineFallback(s.cfg, s.networkErr)
+	}
+	saveSnapshot(s.cfg, s.repos)
+	return RepositoriesMsg{Repositories: s.repos}
+}
+
+// saveSnapshot saves the repositories, keeping the previous snapshot of those that failed
+func saveSnapshot(cfg config.GithubConfig, repos []*github.Repository) {
+	previous, _ := github.LoadSnapshot(cfg)
+	var snapshot []*github.Repository
+	for _, repo := range repos {
+		if repo.Error != nil {
+			repo = github.FindRepository(previous, repo.FullName)
+		}
+		if repo != nil {
+			snapshot = append(snapshot, repo)
+		}
+	}
+
+	if err := github.SaveSnapshot(cfg, snapshot); err != nil {
+		log.Printf("Warning: failed to save snapshot: %v", err)
+	}
+}
+
+// offlineFallback returns the snapshot of a previous session when GitHub cannot be reached
+func offlineFallback(cfg config.GithubConfig, err error) tea.Msg {
+	if repos, found := github.LoadSnapshot(cfg); found {
+		log.Printf("GitHub unreachable, using the last snapshot: %v", err)
+		return RepositoriesMsg{Repositories: repos, Offline: true}
+	}
+	return OfflineMsg{Error: err}
+}
+
+// LoadSnapshot returns the repositories of the previous session, to show them while live data is fetched
+func LoadSnapshot(cfg config.GithubConfig) tea.Cmd {
+	return func() tea.Msg {
+		repos, found := github.LoadSnapshot(cfg)
+		if !found {
+			return nil
+		}
+		return RepositoriesMsg{Repositories: repos, Cached: true}
+	}
+}
+
+// RefreshRepositories fetches the repositories of the config without falling back to the snapshot
+func RefreshRepositories(client *github.Client, cfg config.GithubConfig) tea.Cmd {
+	return func() tea.Msg {
+		return fetchRepositories(client, cfg)
+	}
+}
+
+func fetchRepositories(client *github.Client, cfg config.GithubConfig) tea.Msg {
+	names, discoveryErr := client.ResolveRepositories(cfg)
+	if discoveryErr != nil && !errors.Is(discoveryErr, github.ErrIncompleteDiscovery) {
+		if github.IsNetworkError(discoveryErr) {
+			return OfflineMsg{Error: discoveryErr}
+		}
+		return ErrorMsg{Error: discoveryErr, Request: "resolve the repositories of the config"}
+	}
+	repos, err := client.FetchRepositoriesWithWorkflows(names, cfg)
+	if err != nil {
+		if github.IsNetworkError(err) {
+			return OfflineMsg{Error: err}
+		}
+		return ErrorMsg{Error: err, Request: "fetch the repositories"}
+	}
+
+	saveSnapshot(cfg, repos)
+	return withDiscoveryError(discoveryErr, RepositoriesMsg{
+		Repositories: repos,
+	})
+}
+
+// RetryRepository fetches again a repository that failed to load
+func RetryRepository(client *github.Client, cfg config.GithubConfig, fullName string) tea.Cmd {
+	return func() tea.Msg {
+		repo, err := client.FetchRepository(fullName, cfg.FilterFor(fullName))
+		if err != nil {
+			log.Printf("Error fetching %s: %v", fullName, err)
+		}
+		return RepositoryMsg{
+			Name:       fullName,
+			Repository: repo,
+			Retry:      true,
+		}
+	}
+}
+
+// RetryWorkflow fetches again the runs of a workflow that failed to load
+func RetryWorkflow(client *github.Client, cfg config.GithubConfig, repo *github.Repository, workflow *github.Workflow) tea.Cmd {
+	fullName := repo.FullName
+	return func() tea.Msg {
+		refreshed := client.RefreshWorkflow(fullName, workflow, cfg.FilterFor(fullName))
+		if refreshed.Error != nil {
+			log.Printf("Error fetching runs of %s in %s: %v", refreshed.Name, fullName, refreshed.Error)
+		}
+		return WorkflowRefreshedMsg{
+			Repository: repo,
+			Workflow:   refreshed,
+		}
+	}
+}
+
+// WatchConfig waits for the next change of the config file
+func WatchConfig(changes <-chan config.Change) tea.Cmd {
+	return func() tea.Msg {
+		change, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return ConfigChangedMsg{
+			Config: change.Config,
+			Error:  change.Error,
+			Next:   WatchConfig(changes),
+		}
+	}
+}
+
+// ReloadRepositories resolves the repositories of a new config and fetches those missing from known
+func ReloadRepositories(client *github.Client, cfg config.GithubConfig, known []string, toast string) tea.Cmd {
 	return func() tea.Msg {
-		repos, err := client.FetchRepositoriesWithWorkflows(names)
+		names, err := client.ResolveRepositories(cfg)
+		if err != nil && !errors.Is(err, github.ErrIncompleteDiscovery) {
+			return ErrorMsg{Error: err, Request: "resolve the repositories of the new config"}
+		}
+
+		isKnown := make(map[string]bool, len(known))
+		for _, name := range known {
+			isKnown[strings.ToLower(name)] = true
+		}
+		isResolved := make(map[string]bool, len(names))
+		var added []string
+		for _, name := range names {
+			isResolved[strings.ToLower(name)] = true
+			if !isKnown[strings.ToLower(name)] {
+				added = append(added, name)
+			}
+		}
+		var removed []string
+		for _, name := range known {
+			// A repository missing from an incomplete discovery may still be part of the config
+			if err == nil && !isResolved[strings.ToLower(name)] {
+				removed = append(removed, name)
+			}
+		}
+
+		msg := RepositoriesReloadedMsg{Added: added, Removed: removed, Toast: toast}
+		if len(added) > 0 {
+			stream := &repositoryStream{
+				cfg:     cfg,
+				results: client.StreamRepositoriesWithWorkflows(added, cfg),
+				partial: true,
+			}
+			msg.Next = stream.next
+		}
+		return withDiscoveryError(err, msg)
+	}
+}
+
+// AccessibleRepositoriesMsg lists the repositories the user can access, to complete repository names
+type AccessibleRepositoriesMsg struct {
+	Names []string
+}
+
+// FetchAccessibleRepositories lists the repositories the user can access, an error leaves the list empty
+func FetchAccessibleRepositories(client *github.Client) tea.Cmd {
+	return func() tea.Msg {
+		repos, err := client.FetchAccessibleRepositories()
 		if err != nil {
-			return ErrorMsg{Error: err}
+			log.Printf("Error listing accessible repositories: %v", err)
+			return AccessibleRepositoriesMsg{}
+		}
+		names := make([]string, len(repos))
+		for i, repo := range repos {
+			names[i] = repo.FullName
+		}
+		return AccessibleRepositoriesMsg{Names: names}
+	}
+}
+
+// EditConfig applies an edit to the config file, then reads the config again with the profile applied.
+// The edit returns the confirmation shown once the config is reloaded.
+func EditConfig(profile, request string, edit func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		done, err := edit()
+		if err != nil {
+			return ErrorMsg{Error: err, Request: request}
+		}
+		cfg, err := config.Read(profile)
+		if err != nil {
+			return ConfigChangedMsg{Error: err}
+		}
+		return ConfigChangedMsg{Config: cfg, Toast: done}
+	}
+}
+
+// AddRepository checks that an 'owner/repo' exists before adding it to a group of the config file,
+// or to the ungrouped repositories when group is empty
+func AddRepository(client *github.Client, profile, name, group string) tea.Cmd {
+	return EditConfig(profile, "add "+name, func() (string, error) {
+		entry, err := config.ParseEntry(name)
+		if err != nil {
+			return "", err
+		}
+		if entry.Kind != config.RepositoryEntry {
+			return "", fmt.Errorf("expected an 'owner/repo' name, got '%s'", name)
+		}
+		repo, err := client.FetchRepositoryInfo(entry.Owner, entry.Name)
+		if err != nil {
+			return "", err
+		}
+		if err := config.AddRepository(repo.FullName, group); err != nil {
+			return "", err
+		}
+		return "Added " + repo.FullName, nil
+	})
+}
+
+// Reconnect schedules the next attempt to fetch live data
+func Reconnect() tea.Cmd {
+	return tea.Tick(reconnectInterval, func(time.Time) tea.Msg {
+		return ReconnectMsg{}
+	})
+}
+
+// PollMsg asks to fetch the repositories again to notify the runs completed since, ID tells
+// the polling loops apart when the interval changes
+type PollMsg struct {
+	ID int
+}
+
+// Poll schedules the next fetch of the polling loop
+func Poll(interval time.Duration, id int) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return PollMsg{ID: id}
+	})
+}
+
+// Notify delivers notifications outside of the TUI, failures are only logged
+func Notify(backend notify.Backend, notifications []notify.Notification) tea.Cmd {
+	if backend == nil || len(notifications) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := notify.Send(backend, notifications...); err != nil {
+			log.Printf("Failed to notify: %v", err)
+		}
+		return nil
+	}
+}
+
+// NotificationToast shows notifications in a toast, the first one followed by the count of the others
+func NotificationToast(notifications []notify.Notification) tea.Cmd {
+	if len(notifications) == 0 {
+		return nil
+	}
+	first := notifications[0]
+	message := first.Title + ": " + first.Body
+	if len(notifications) > 1 {
+		message += fmt.Sprintf(" (+%d more)", len(notifications)-1)
+	}
+	return Toast(ToastInfo, message)
+}
+
+// FetchRepositoryWithRun fetches a run of a repository, of a specific attempt when attempt is not zero
+func FetchRepositoryWithRun(client *github.Client, fullName string, runID int64, attempt int) tea.Cmd {
+	return func() tea.Msg {
+		request := fmt.Sprintf("fetch run %d of %s", runID, fullName)
+		owner, repo, ok := strings.Cut(fullName, "/")
+		if !ok {
+			return ErrorMsg{
+				Error:   fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", fullName),
+				Request: request,
+			}
+		}
+		repository, err := client.FetchRepositoryWithRun(owner, repo, runID, attempt)
+		if err != nil {
+			return ErrorMsg{Error: err, Request: request}
 		}
 		return RepositoriesMsg{
-			Repositories: repos,
+			Repositories: []*github.Repository{repository},
 		}
 	}
 }
@@ -80,16 +562,18 @@ func FetchStepLogs(client *github.Client, job *github.Job) tea.Cmd {
 	return func() tea.Msg {
 		if job == nil {
 			return ErrorMsg{
-				Error: fmt.Errorf("workflow run is nil"),
+				Error:   fmt.Errorf("workflow run is nil"),
+				Request: "fetch the logs of the selected job",
 			}
 		}
+		request := fmt.Sprintf("fetch the logs of job %s", job.Name)
 		info, err := github.ParseGitHubURL(job.GetURL())
 		if err != nil {
-			return ErrorMsg{Error: err}
+			return ErrorMsg{Error: err, Request: request}
 		}
-		steps, err := client.GetLogs(info.User, info.Repo, info.RunID, "1", job.Name)
+		steps, err := client.GetLogs(info.User, info.Repo, info.RunID, info.LogAttempt(job), job.Name)
 		if err != nil {
-			return ErrorMsg{Error: err}
+			return ErrorMsg{Error: err, Request: request}
 		}
 		return LogsMsg{
 			Steps: steps,
@@ -101,16 +585,18 @@ func FetchLogs(client *github.Client, job *github.Job) tea.Cmd {
 	return func() tea.Msg {
 		if job == nil {
 			return ErrorMsg{
-				Error: fmt.Errorf("workflow run is nil"),
+				Error:   fmt.Errorf("workflow run is nil"),
+				Request: "fetch the logs of the selected job",
 			}
 		}
+		request := fmt.Sprintf("fetch the logs of job %s", job.Name)
 		info, err := github.ParseGitHubURL(job.GetURL())
 		if err != nil {
-			return ErrorMsg{Error: err}
+			return ErrorMsg{Error: err, Request: request}
 		}
-		steps, err := client.GetLogs(info.User, info.Repo, info.RunID, "1", job.Name)
+		steps, err := client.GetLogs(info.User, info.Repo, info.RunID, info.LogAttempt(job), job.Name)
 		if err != nil {
-			return ErrorMsg{Error: err}
+			return ErrorMsg{Error: err, Request: request}
 		}
 		return LogsMsg{
 			Steps: steps,
@@ -123,7 +609,8 @@ func GoToStep(row github.RowData) tea.Cmd {
 		runWithJobs, ok := row.(*github.Job)
 		if !ok {
 			return ErrorMsg{
-				Error: fmt.Errorf("selected row is not a workflow"),
+				Error:   fmt.Errorf("selected row is not a workflow"),
+				Request: "open the selected row",
 			}
 		}
 		return GotostepMsg{
@@ -137,7 +624,8 @@ func GoToWorkflow(row github.RowData) tea.Cmd {
 		workflows, ok := row.(*github.Repository)
 		if !ok {
 			return ErrorMsg{
-				Error: fmt.Errorf("selected row is not a repository"),
+				Error:   fmt.Errorf("selected row is not a repository"),
+				Request: "open the selected row",
 			}
 		}
 		return WorkflowsMsg{
@@ -151,7 +639,8 @@ func GoToRun(row github.RowData) tea.Cmd {
 		runWithJobs, ok := row.(*github.WorkflowRun)
 		if !ok {
 			return ErrorMsg{
-				Error: fmt.Errorf("selected row is not a workflow"),
+				Error:   fmt.Errorf("selected row is not a workflow"),
+				Request: "open the selected row",
 			}
 		}
 		return WorkflowRunMsg{
@@ -184,9 +673,10 @@ func OpenBrowser(url string) tea.Cmd {
 	return tea.ExecProcess(cmd, func(err error) tea.Msg {
 		if err != nil {
 			return ErrorMsg{
-				Error: fmt.Errorf("failed to open browser: %w", err),
+				Error:   fmt.Errorf("failed to open browser: %w", err),
+				Request: "open " + url,
 			}
 		}
-		return nil
+		return ToastMsg{Message: "Opened in the browser", Level: ToastSuccess}
 	})
 }
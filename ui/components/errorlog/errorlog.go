@@ -0,0 +1,148 @@
+package errorlog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/cpaluszek/gh-ci/ui/keys"
+)
+
+const maxWidth = 100
+
+// Entry is an error of the session
+type Entry struct {
+	Time time.Time
+	// Request describes what was being done when the error occurred
+	Request string
+	Error   error
+}
+
+// Model lists the errors of the session, most recent first
+type Model struct {
+	ctx      *context.Context
+	entries  []Entry
+	viewport viewport.Model
+	active   bool
+}
+
+func NewModel(ctx *context.Context) Model {
+	return Model{
+		ctx:      ctx,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// Add records an error, it is shown the next time the log is opened
+func (m *Model) Add(request string, err error) {
+	m.entries = append(m.entries, Entry{
+		Time:    time.Now(),
+		Request: request,
+		Error:   err,
+	})
+	m.syncViewport()
+}
+
+func (m Model) Len() int {
+	return len(m.entries)
+}
+
+func (m *Model) Open() {
+	m.active = true
+	m.syncViewport()
+	m.viewport.GotoTop()
+}
+
+func (m *Model) Close() {
+	m.active = false
+}
+
+func (m Model) IsActive() bool {
+	return m.active
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		// The context holds the new size already
+		m.syncViewport()
+		return m, nil
+	}
+	if !m.active {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Keys.Return), key.Matches(keyMsg, keys.Keys.ErrorLog), key.Matches(keyMsg, keys.Keys.Quit):
+			m.Close()
+			return m, nil
+		case key.Matches(keyMsg, keys.Keys.Up):
+			m.viewport.ScrollUp(1)
+			return m, nil
+		case key.Matches(keyMsg, keys.Keys.Down):
+			m.viewport.ScrollDown(1)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	width, innerWidth := m.widths()
+
+	title := m.ctx.Styles.Title.Render(fmt.Sprintf("Errors (%d)", len(m.entries)))
+	lines := []string{
+		title,
+		m.ctx.Styles.Header.Width(innerWidth).Render(""),
+		m.viewport.View(),
+	}
+
+	return m.ctx.Styles.Finder.Width(width).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
+
+// widths returns the width of the log and of its content
+func (m Model) widths() (int, int) {
+	width := min(maxWidth, m.ctx.ScreenWidth-4)
+	return width, width - 4
+}
+
+// syncViewport sizes the viewport to the screen and fills it with the entries,
+// keeping the scroll position when possible
+func (m *Model) syncViewport() {
+	_, innerWidth := m.widths()
+	content := m.renderEntries(innerWidth)
+
+	m.viewport.Width = innerWidth
+	m.viewport.Height = max(min(m.ctx.MainContentHeight-6, lipgloss.Height(content)), 1)
+	m.viewport.SetContent(content)
+}
+
+func (m Model) renderEntries(width int) string {
+	if len(m.entries) == 0 {
+		return m.ctx.Styles.Skipped.Render("No errors")
+	}
+
+	var blocks []string
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		request := entry.Request
+		if request == "" {
+			request = "unknown request"
+		}
+		header := m.ctx.Styles.Skipped.Render(entry.Time.Format("15:04:05")) + "  " +
+			m.ctx.Styles.Title.Render(request)
+		message := m.ctx.Styles.Error.Width(width).Render(strings.TrimSpace(entry.Error.Error()))
+		blocks = append(blocks, lipgloss.JoinVertical(lipgloss.Left, header, message, ""))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+}
@@ -0,0 +1,251 @@
+package finder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/ui/commands"
+	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	maxWidth   = 100
+	maxResults = 15
+)
+
+type ItemKind string
+
+const (
+	RepositoryItem ItemKind = "repo"
+	WorkflowItem   ItemKind = "workflow"
+	RunItem        ItemKind = "run"
+	JobItem        ItemKind = "job"
+)
+
+// Item is a single searchable entry pointing at a location in the loaded data
+type Item struct {
+	Kind       ItemKind
+	Text       string
+	Repository *github.Repository
+	Run        *github.WorkflowRun
+	Job        *github.Job
+}
+
+type items []Item
+
+func (it items) String(i int) string {
+	return it[i].Text
+}
+
+func (it items) Len() int {
+	return len(it)
+}
+
+type Model struct {
+	ctx     *context.Context
+	input   textinput.Model
+	items   items
+	matches fuzzy.Matches
+	cursor  int
+	active  bool
+}
+
+func NewModel(ctx *context.Context) Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Search repositories, workflows, runs and jobs"
+
+	return Model{
+		ctx:   ctx,
+		input: input,
+	}
+}
+
+// Open indexes the given repositories and focuses the search input
+func (m *Model) Open(repos []*github.Repository) tea.Cmd {
+	m.items = BuildItems(repos)
+	m.input.SetValue("")
+	m.active = true
+	m.filter()
+	return m.input.Focus()
+}
+
+func (m *Model) Close() {
+	m.active = false
+	m.input.Blur()
+	m.items = nil
+	m.matches = nil
+}
+
+func (m Model) IsActive() bool {
+	return m.active
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		m.Close()
+		return m, nil
+	case tea.KeyEnter:
+		if m.cursor < 0 || m.cursor >= len(m.matches) {
+			return m, nil
+		}
+		item := m.items[m.matches[m.cursor].Index]
+		m.Close()
+		return m, jumpTo(item)
+	case tea.KeyUp, tea.KeyCtrlP, tea.KeyCtrlK:
+		m.cursor = max(m.cursor-1, 0)
+		return m, nil
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyCtrlJ:
+		m.cursor = min(m.cursor+1, max(len(m.matches)-1, 0))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	previous := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.filter()
+	}
+	return m, cmd
+}
+
+func (m *Model) filter() {
+	m.cursor = 0
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" {
+		m.matches = make(fuzzy.Matches, len(m.items))
+		for i := range m.items {
+			m.matches[i] = fuzzy.Match{Str: m.items[i].Text, Index: i}
+		}
+		return
+	}
+	m.matches = fuzzy.FindFrom(query, m.items)
+}
+
+func (m Model) View() string {
+	width := min(maxWidth, m.ctx.ScreenWidth-4)
+	m.input.Width = width - 4
+
+	lines := []string{
+		m.input.View(),
+		m.ctx.Styles.Header.Width(width - 2).Render(""),
+	}
+
+	if len(m.matches) == 0 {
+		lines = append(lines, m.ctx.Styles.Skipped.Render("No results"))
+	}
+
+	numResults := min(maxResults, max(m.ctx.MainContentHeight-6, 1))
+	start := max(0, m.cursor-numResults+1)
+	end := min(len(m.matches), start+numResults)
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderMatch(m.matches[i], i == m.cursor, width-2))
+	}
+
+	if len(m.matches) > end {
+		lines = append(lines, m.ctx.Styles.Skipped.Render(fmt.Sprintf("+ %d more", len(m.matches)-end)))
+	}
+
+	return m.ctx.Styles.Finder.Width(width).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
+
+func (m Model) renderMatch(match fuzzy.Match, selected bool, width int) string {
+	item := m.items[match.Index]
+
+	style := m.ctx.Styles.Row
+	if selected {
+		style = m.ctx.Styles.SelectedRow
+	}
+	highlight := style.Inherit(m.ctx.Styles.Match)
+
+	matched := make(map[int]bool, len(match.MatchedIndexes))
+	for _, index := range match.MatchedIndexes {
+		matched[index] = true
+	}
+
+	var text strings.Builder
+	for i, r := range item.Text {
+		if matched[i] {
+			text.WriteString(highlight.Render(string(r)))
+		} else {
+			text.WriteString(style.Render(string(r)))
+		}
+	}
+
+	kind := style.Inherit(m.ctx.Styles.Skipped).Width(10).Render(string(item.Kind))
+	return style.Width(width).MaxWidth(width).Render(kind + text.String())
+}
+
+func jumpTo(item Item) tea.Cmd {
+	return func() tea.Msg {
+		return commands.JumpToMsg{
+			Repository: item.Repository,
+			Run:        item.Run,
+			Job:        item.Job,
+		}
+	}
+}
+
+// BuildItems indexes every repository, workflow, run and job already loaded
+func BuildItems(repos []*github.Repository) items {
+	var result items
+	for _, repo := range repos {
+		result = append(result, Item{
+			Kind:       RepositoryItem,
+			Text:       repo.FullName,
+			Repository: repo,
+		})
+
+		for _, workflow := range repo.Workflows {
+			if len(workflow.Runs) == 0 {
+				continue
+			}
+			result = append(result, Item{
+				Kind:       WorkflowItem,
+				Text:       repo.Name + " › " + workflow.Name,
+				Repository: repo,
+				Run:        workflow.Runs[0],
+			})
+
+			for _, run := range workflow.Runs {
+				commitMsg := strings.Split(run.HeadCommit.Message, "\n")[0]
+				result = append(result, Item{
+					Kind:       RunItem,
+					Text:       fmt.Sprintf("%s › %s · %s · %s", repo.Name, run.DisplayTitle, run.HeadBranch, commitMsg),
+					Repository: repo,
+					Run:        run,
+				})
+
+				for _, job := range run.Jobs {
+					result = append(result, Item{
+						Kind:       JobItem,
+						Text:       fmt.Sprintf("%s › %s › %s", repo.Name, run.DisplayTitle, job.Name),
+						Repository: repo,
+						Run:        run,
+						Job:        job,
+					})
+				}
+			}
+		}
+	}
+	return result
+}
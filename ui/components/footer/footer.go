@@ -4,6 +4,7 @@ import (
 	bbhelp "github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/cpaluszek/gh-ci/ui/context"
 	"github.com/cpaluszek/gh-ci/ui/keys"
 )
@@ -27,7 +28,7 @@ func NewModel(ctx *context.Context) Model {
 		content:              "",
 		width:                0,
 		ShowQuitConfirmation: false,
-		quitConfirmation:     "Press q/esc again to quit",
+		quitConfirmation:     "Press " + keys.Keys.Quit.Help().Key + " again to quit",
 		Help:                 help,
 	}
 }
@@ -58,11 +59,31 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 func (m Model) View() string {
 	if m.ShowQuitConfirmation {
 		return m.ctx.Styles.Footer.Width(m.width).Render(m.quitConfirmation)
+	} else if status := m.status(); status != "" {
+		m.Help.Width = m.width - lipgloss.Width(status)
+		return m.ctx.Styles.Footer.Width(m.width).Render(
+			lipgloss.JoinHorizontal(lipgloss.Top, status, m.Help.View(keys.Keys)),
+		)
 	} else {
 		return m.ctx.Styles.Footer.Width(m.width).Render(m.Help.View(keys.Keys))
 	}
 }
 
+// status describes where the displayed data comes from when it is not live
+func (m Model) status() string {
+	switch {
+	case m.ctx.Error != nil:
+		return m.ctx.Styles.Error.Render("Error, press "+keys.Keys.ErrorLog.Help().Key+" for details") + "  "
+	case m.ctx.Offline:
+		return m.ctx.Styles.Warning.Render("Offline, showing cached data") + "  "
+	case m.ctx.Refreshing:
+		return m.ctx.Styles.Skipped.Render("Refreshing…") + "  "
+	case m.ctx.ShowHidden:
+		return m.ctx.Styles.Skipped.Render("Showing hidden workflows") + "  "
+	}
+	return ""
+}
+
 func (m *Model) SetWidth(width int) {
 	m.width = width
     m.Help.Width = width
@@ -62,6 +62,8 @@ func (m *Model) PrevItem() int {
 
 func (m *Model) FirstItem() int {
 	m.currentId = 0
+	m.topBoundId = 0
+	m.bottomBoundId = min(m.GetNumItemsDisplayed()-1, m.NumRows-1)
 	m.viewport.GotoTop()
 	return m.currentId
 }
@@ -76,6 +78,23 @@ func (m *Model) GetCurrItem() int {
 	return m.currentId
 }
 
+func (m *Model) SetCurrItem(id int) int {
+	if m.NumRows == 0 {
+		return m.FirstItem()
+	}
+
+	m.currentId = max(0, min(id, m.NumRows-1))
+	numDisplayed := max(m.GetNumItemsDisplayed(), 1)
+	if m.currentId < m.topBoundId {
+		m.topBoundId = m.currentId
+	} else if m.currentId >= m.topBoundId+numDisplayed {
+		m.topBoundId = m.currentId - numDisplayed + 1
+	}
+	m.bottomBoundId = m.topBoundId + numDisplayed - 1
+	m.viewport.SetYOffset(m.topBoundId * m.listItemHeight)
+	return m.currentId
+}
+
 func (m *Model) GetNumItemsDisplayed() int {
 	if m.listItemHeight == 0 {
 		return 0
@@ -0,0 +1,174 @@
+package prompt
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	maxWidth       = 80
+	maxSuggestions = 8
+)
+
+// Model is a single line input completed from fuzzy matched suggestions
+type Model struct {
+	ctx         *context.Context
+	title       string
+	input       textinput.Model
+	suggestions []string
+	matches     fuzzy.Matches
+	// cursor is the highlighted suggestion, -1 while the typed value is used
+	cursor int
+	active bool
+	submit func(value string) tea.Cmd
+}
+
+func NewModel(ctx *context.Context) Model {
+	input := textinput.New()
+	input.Prompt = "> "
+
+	return Model{
+		ctx:    ctx,
+		input:  input,
+		cursor: -1,
+	}
+}
+
+// Open focuses the input, submit is run with the value entered unless the prompt is canceled
+func (m *Model) Open(title, placeholder string, submit func(value string) tea.Cmd) tea.Cmd {
+	m.title = title
+	m.input.Placeholder = placeholder
+	m.input.SetValue("")
+	m.suggestions = nil
+	m.submit = submit
+	m.active = true
+	m.filter()
+	return m.input.Focus()
+}
+
+func (m *Model) Close() {
+	m.active = false
+	m.input.Blur()
+	m.suggestions = nil
+	m.matches = nil
+	m.submit = nil
+}
+
+func (m Model) IsActive() bool {
+	return m.active
+}
+
+// SetSuggestions replaces the values offered while typing
+func (m *Model) SetSuggestions(suggestions []string) {
+	m.suggestions = suggestions
+	m.filter()
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		m.Close()
+		return m, nil
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.input.Value())
+		if m.cursor >= 0 && m.cursor < len(m.matches) {
+			value = m.matches[m.cursor].Str
+		}
+		if value == "" {
+			return m, nil
+		}
+		submit := m.submit
+		m.Close()
+		return m, submit(value)
+	case tea.KeyTab:
+		if len(m.matches) == 0 {
+			return m, nil
+		}
+		m.input.SetValue(m.matches[max(m.cursor, 0)].Str)
+		m.input.CursorEnd()
+		m.filter()
+		return m, nil
+	case tea.KeyUp, tea.KeyCtrlP, tea.KeyCtrlK:
+		m.cursor = max(m.cursor-1, -1)
+		return m, nil
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyCtrlJ:
+		m.cursor = min(m.cursor+1, min(len(m.matches), maxSuggestions)-1)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	previous := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.filter()
+	}
+	return m, cmd
+}
+
+func (m *Model) filter() {
+	m.cursor = -1
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" {
+		m.matches = nil
+		return
+	}
+	m.matches = fuzzy.Find(query, m.suggestions)
+}
+
+func (m Model) View() string {
+	width := min(maxWidth, m.ctx.ScreenWidth-4)
+	m.input.Width = width - 4
+
+	lines := []string{
+		m.ctx.Styles.Title.Render(m.title),
+		m.input.View(),
+		m.ctx.Styles.Header.Width(width - 2).Render(""),
+	}
+	for i, match := range m.matches[:min(len(m.matches), maxSuggestions)] {
+		lines = append(lines, m.renderMatch(match, i == m.cursor, width-2))
+	}
+	lines = append(lines, m.ctx.Styles.Skipped.Render("tab complete · enter confirm · esc cancel"))
+
+	return m.ctx.Styles.Finder.Width(width).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
+
+func (m Model) renderMatch(match fuzzy.Match, selected bool, width int) string {
+	style := m.ctx.Styles.Row
+	if selected {
+		style = m.ctx.Styles.SelectedRow
+	}
+	highlight := style.Inherit(m.ctx.Styles.Match)
+
+	matched := make(map[int]bool, len(match.MatchedIndexes))
+	for _, index := range match.MatchedIndexes {
+		matched[index] = true
+	}
+
+	var text strings.Builder
+	for i, r := range match.Str {
+		if matched[i] {
+			text.WriteString(highlight.Render(string(r)))
+		} else {
+			text.WriteString(style.Render(string(r)))
+		}
+	}
+	return style.Width(width).MaxWidth(width).Render(text.String())
+}
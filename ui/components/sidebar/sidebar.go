@@ -10,6 +10,7 @@ import (
 	"github.com/cpaluszek/gh-ci/github"
 	"github.com/cpaluszek/gh-ci/ui/constants"
 	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/cpaluszek/gh-ci/ui/keys"
 	"github.com/cpaluszek/gh-ci/ui/utils"
 )
 
@@ -74,6 +75,25 @@ func (m *Model) GenerateRepoSidebarContent(repo *github.Repository) {
 		"",
 	}
 
+	if m.ctx.Offline && !repo.FetchedAt.IsZero() {
+		content = append(content,
+			m.ctx.Styles.Warning.Render("Offline, fetched "+utils.FormatTime(repo.FetchedAt)),
+			"",
+		)
+	}
+
+	if repo.Error != nil {
+		content = append(content, m.errorContent(repo.Error)...)
+		if repo.FetchedAt.IsZero() {
+			m.SetContent(lipgloss.JoinVertical(lipgloss.Left, content...))
+			return
+		}
+		content = append(content,
+			m.ctx.Styles.Default.Render("Showing data fetched "+utils.FormatTime(repo.FetchedAt)),
+			"",
+		)
+	}
+
 	// If no workflows, show message and return
 	if len(repo.Workflows) == 0 || len(repo.Workflows[0].Runs) == 0 {
 		content = append(content, m.ctx.Styles.Default.Render("No workflows found"))
@@ -139,6 +159,36 @@ func (m *Model) GenerateWorkflowSidebarContent(workflow *github.WorkflowRun) {
 	m.SetContent(lipgloss.JoinVertical(lipgloss.Left, content...))
 }
 
+// GenerateWorkflowErrorSidebarContent shows why the runs of a workflow failed to load
+func (m *Model) GenerateWorkflowErrorSidebarContent(workflow *github.Workflow) {
+	content := []string{
+		m.ctx.Styles.Title.Render("Workflow: " + workflow.GetName()),
+		"",
+	}
+	content = append(content, m.errorContent(workflow.Error)...)
+
+	m.SetContent(lipgloss.JoinVertical(lipgloss.Left, content...))
+}
+
+// errorContent describes a fetch error, with its HTTP status when it comes from the API
+func (m *Model) errorContent(err error) []string {
+	width := constants.SideBarWidth - 4
+	title := "Failed to load"
+	message := err.Error()
+	if status, statusMessage := github.ErrorStatus(err); status != 0 {
+		title = fmt.Sprintf("Failed to load: HTTP %d", status)
+		message = statusMessage
+	}
+
+	return []string{
+		m.ctx.Styles.Error.Render(title),
+		m.ctx.Styles.Default.Width(width).Render(message),
+		"",
+		m.ctx.Styles.Default.Render("Press " + keys.Keys.Retry.Help().Key + " to retry"),
+		"",
+	}
+}
+
 func (m *Model) GenerateRunSidebarContent(run *github.Job) {
 	content := []string{
 		m.ctx.Styles.Title.Render("Run: " + run.GetName()),
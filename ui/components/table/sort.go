@@ -0,0 +1,107 @@
+package table
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is the typed value a row is ordered by for a given column.
+// Supported types are string, int, int64, float64, bool, time.Duration and time.Time.
+type SortKey any
+
+// Sort describes the column rows are ordered by; a negative Column keeps the data order
+type Sort struct {
+	Column int
+	Desc   bool
+}
+
+var Unsorted = Sort{Column: -1}
+
+func (s Sort) IsSorted() bool {
+	return s.Column >= 0
+}
+
+func (m Model) GetSort() Sort {
+	return m.sort
+}
+
+func (m *Model) SetSort(s Sort) {
+	if s.Column >= len(m.Columns) {
+		s = Unsorted
+	}
+	m.sort = s
+	m.SyncViewPortContent()
+}
+
+// CycleSortColumn moves the sort to the next titled column, back to the data order after the last one
+func (m *Model) CycleSortColumn() {
+	for column := m.sort.Column + 1; column < len(m.Columns); column++ {
+		if m.Columns[column].Title == "" {
+			continue
+		}
+		m.SetSort(Sort{Column: column, Desc: m.sort.Desc})
+		return
+	}
+	m.SetSort(Unsorted)
+}
+
+func (m *Model) ToggleSortOrder() {
+	if !m.sort.IsSorted() {
+		m.CycleSortColumn()
+	}
+	m.SetSort(Sort{Column: m.sort.Column, Desc: !m.sort.Desc})
+}
+
+// SortItems orders items in place by the table's current sort, using key to extract the typed value of a column
+func SortItems[T any](m *Model, items []T, key func(item T, column int) SortKey) {
+	s := m.GetSort()
+	if !s.IsSorted() {
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		c := CompareSortKeys(key(items[i], s.Column), key(items[j], s.Column))
+		if s.Desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// CompareSortKeys compares two keys of the same type, strings being compared case-insensitively
+func CompareSortKeys(a, b SortKey) int {
+	switch a := a.(type) {
+	case string:
+		b, _ := b.(string)
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	case int:
+		return compareOrdered(a, b)
+	case int64:
+		return compareOrdered(a, b)
+	case float64:
+		return compareOrdered(a, b)
+	case time.Duration:
+		return compareOrdered(a, b)
+	case bool:
+		b, _ := b.(bool)
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		default:
+			return 1
+		}
+	case time.Time:
+		b, _ := b.(time.Time)
+		return a.Compare(b)
+	}
+	return 0
+}
+
+func compareOrdered[T cmp.Ordered](a T, b SortKey) int {
+	other, _ := b.(T)
+	return cmp.Compare(a, other)
+}
@@ -17,9 +17,12 @@ type Model struct {
 	Columns      []Column
 	Dimensions   constants.Dimensions
 	rowsViewport listviewport.Model
+	sort         Sort
 	// Empty state
 	isLoading      bool
 	loadingSpinner spinner.Model
+	// rowsLoading keeps the spinner running for rows still being loaded
+	rowsLoading bool
 }
 
 type Row []string
@@ -54,6 +57,7 @@ func NewModel(
 			len(rows),
 			constants.TableRowHeight,
 		),
+		sort:           Unsorted,
 		isLoading:      isLoading,
 		loadingSpinner: loadingSpinner,
 	}
@@ -61,7 +65,7 @@ func NewModel(
 
 func (m *Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
-	if m.isLoading {
+	if m.isLoading || m.rowsLoading {
 		m.loadingSpinner, cmd = m.loadingSpinner.Update(msg)
 	}
 	return *m, cmd
@@ -79,6 +83,16 @@ func (m Model) IsLoading() bool {
 	return m.isLoading
 }
 
+// SetRowsLoading keeps the spinner running while some rows are loading
+func (m *Model) SetRowsLoading(val bool) {
+	m.rowsLoading = val
+}
+
+// SpinnerView renders the loading spinner, for rows to show their own loading state
+func (m Model) SpinnerView() string {
+	return m.loadingSpinner.View()
+}
+
 func (m *Model) SetDimensions(dimensions constants.Dimensions) {
 	m.Dimensions = dimensions
 	m.rowsViewport.SetDimensions(
@@ -132,6 +146,14 @@ func (m *Model) GetCurrItem() int {
 	return m.rowsViewport.GetCurrItem()
 }
 
+func (m *Model) SetCurrItem(id int) int {
+	m.SyncViewPortContent()
+	currItem := m.rowsViewport.SetCurrItem(id)
+	m.SyncViewPortContent()
+
+	return currItem
+}
+
 func (m *Model) SetRows(rows []Row) {
 	m.Rows = rows
 	m.rowsViewport.SetNumRows(len(rows))
@@ -165,12 +187,12 @@ func (m Model) renderHeaderColumns() []string {
 			renderedColumns[i] = m.ctx.Styles.Header.
 				Width(m.Columns[i].Width).
 				MaxWidth(m.Columns[i].Width).
-				Render(m.Columns[i].Title)
+				Render(m.columnTitle(i))
 			takenWidth += m.Columns[i].Width
 			continue
 		}
 
-		cell := m.ctx.Styles.Header.Render(m.Columns[i].Title)
+		cell := m.ctx.Styles.Header.Render(m.columnTitle(i))
 		renderedColumns[i] = cell
 		takenWidth += lipgloss.Width(cell)
 	}
@@ -188,11 +210,22 @@ func (m Model) renderHeaderColumns() []string {
 		renderedColumns[i] = m.ctx.Styles.Header.
 			Width(growWidth).
 			MaxWidth(growWidth).
-			Render(m.Columns[i].Title)
+			Render(m.columnTitle(i))
 	}
 	return renderedColumns
 }
 
+func (m Model) columnTitle(column int) string {
+	title := m.Columns[column].Title
+	if m.sort.Column != column {
+		return title
+	}
+	if m.sort.Desc {
+		return title + " " + m.ctx.Theme.Symbols.SortDescending
+	}
+	return title + " " + m.ctx.Theme.Symbols.SortAscending
+}
+
 func (m Model) renderHeader() string {
 	headerColumns := m.renderHeaderColumns()
 	header := lipgloss.JoinHorizontal(
@@ -225,7 +258,7 @@ func (m Model) renderBody() string {
 			m.Dimensions.Height-constants.TableHeaderHeight,
 			lipgloss.Center,
 			lipgloss.Center,
-			fmt.Sprintf("%s Loading...", m.loadingSpinner.View()),
+			m.ctx.Styles.Skipped.Render("Nothing to show"),
 		)
 	}
 	return m.rowsViewport.View()
@@ -269,5 +302,6 @@ func (m *Model) renderRow(rowId int, headerColumns []string) string {
 
 func (m *Model) UpdateContext(ctx *context.Context) {
 	m.ctx = ctx
+	m.loadingSpinner.Style = ctx.Styles.Spinner
 	m.rowsViewport.UpdateContext(ctx)
 }
@@ -0,0 +1,136 @@
+package toast
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cpaluszek/gh-ci/ui/commands"
+	"github.com/cpaluszek/gh-ci/ui/context"
+)
+
+const (
+	maxWidth   = 50
+	maxToasts  = 3
+	duration   = 4 * time.Second
+	errorDelay = 8 * time.Second
+)
+
+type toast struct {
+	id      int
+	message string
+	level   commands.ToastLevel
+}
+
+// dismissMsg removes a toast once its duration has elapsed
+type dismissMsg struct {
+	id int
+}
+
+// Model stacks transient notifications in the bottom right corner of the screen
+type Model struct {
+	ctx    *context.Context
+	toasts []toast
+	nextID int
+}
+
+func NewModel(ctx *context.Context) Model {
+	return Model{
+		ctx: ctx,
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case commands.ToastMsg:
+		return m, m.push(msg.Message, msg.Level)
+
+	case commands.ErrorMsg:
+		return m, m.push(msg.Error.Error(), commands.ToastError)
+
+	case dismissMsg:
+		for i, t := range m.toasts {
+			if t.id == msg.id {
+				m.toasts = append(m.toasts[:i:i], m.toasts[i+1:]...)
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) push(message string, level commands.ToastLevel) tea.Cmd {
+	// Only the first line fits in a toast, the error log has the rest
+	message, _, _ = strings.Cut(message, "\n")
+
+	m.nextID++
+	id := m.nextID
+	m.toasts = append(m.toasts, toast{id: id, message: message, level: level})
+	if len(m.toasts) > maxToasts {
+		m.toasts = m.toasts[len(m.toasts)-maxToasts:]
+	}
+
+	delay := duration
+	if level == commands.ToastError {
+		delay = errorDelay
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return dismissMsg{id: id}
+	})
+}
+
+func (m Model) View() string {
+	if len(m.toasts) == 0 {
+		return ""
+	}
+
+	width := min(maxWidth, m.ctx.ScreenWidth-2)
+	var rendered []string
+	for _, t := range m.toasts {
+		rendered = append(rendered, m.renderToast(t, width))
+	}
+	return lipgloss.JoinVertical(lipgloss.Right, rendered...)
+}
+
+func (m Model) renderToast(t toast, width int) string {
+	color := m.ctx.Theme.Colors.Info
+	switch t.level {
+	case commands.ToastSuccess:
+		color = m.ctx.Theme.Colors.Success
+	case commands.ToastWarning:
+		color = m.ctx.Theme.Colors.Warning
+	case commands.ToastError:
+		color = m.ctx.Theme.Colors.Error
+	}
+
+	return m.ctx.Styles.Toast.
+		BorderForeground(color).
+		MaxWidth(width).
+		Render(ansi.Truncate(t.message, width-4, "…"))
+}
+
+// Overlay draws the toasts over the bottom right corner of content
+func (m Model) Overlay(content string) string {
+	view := m.View()
+	if view == "" {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	toastLines := strings.Split(view, "\n")
+	toastWidth := lipgloss.Width(view)
+	start := max(len(lines)-len(toastLines), 0)
+	for i, toastLine := range toastLines {
+		if start+i >= len(lines) {
+			break
+		}
+		line := lines[start+i]
+		left := max(lipgloss.Width(line), m.ctx.ScreenWidth) - toastWidth
+		// Pad the line so that toasts line up with the right edge of the screen
+		line += strings.Repeat(" ", max(left-lipgloss.Width(line), 0))
+		lines[start+i] = ansi.Truncate(line, left, "") + toastLine
+	}
+	return strings.Join(lines, "\n")
+}
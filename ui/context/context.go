@@ -27,4 +27,29 @@ type Context struct {
 	MainContentWidth  int
 	MainContentHeight int
 	View              ViewType
+	// Branch is the branch workflow runs are filtered on when FilterByBranch is set
+	Branch         string
+	FilterByBranch bool
+	// Offline is set while the data comes from the snapshot of a previous session
+	Offline bool
+	// Refreshing is set while the snapshot of a previous session is revalidated
+	Refreshing bool
+	// Group is the only group of repositories listed, all groups are listed when empty
+	Group string
+	// Profile is the profile of the config file applied to Config, if any
+	Profile string
+	// EditableConfig is set when Config comes from the config file, which the UI can then edit
+	EditableConfig bool
+	// ShowHidden is set while the workflows and runs hidden by the filters of the config are shown
+	ShowHidden bool
+}
+
+// FetchConfig returns the github config repositories are fetched with, without its filters
+// while the hidden workflows and runs are shown
+func (c *Context) FetchConfig() config.GithubConfig {
+	cfg := c.Config.Github
+	if c.ShowHidden {
+		cfg.Filters = nil
+	}
+	return cfg
 }
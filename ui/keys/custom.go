@@ -0,0 +1,126 @@
+package keys
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// action is a binding as named in the 'keys' section of the config file
+type action struct {
+	name    string
+	binding *key.Binding
+}
+
+func (k *KeyMap) actions() []action {
+	return []action{
+		{"up", &k.Up},
+		{"down", &k.Down},
+		{"select", &k.Select},
+		{"quit", &k.Quit},
+		{"return", &k.Return},
+		{"open_github", &k.OpenGitHub},
+		{"help", &k.Help},
+		{"find", &k.Find},
+		{"sort", &k.Sort},
+		{"sort_order", &k.SortOrder},
+		{"branch", &k.Branch},
+		{"retry", &k.Retry},
+		{"error_log", &k.ErrorLog},
+		{"theme", &k.Theme},
+		{"group", &k.Group},
+		{"add", &k.Add},
+		{"remove", &k.Remove},
+		{"pin", &k.Pin},
+		{"move_up", &k.MoveUp},
+		{"move_down", &k.MoveDown},
+		{"hidden", &k.Hidden},
+	}
+}
+
+// Actions returns the names of the actions that can be rebound
+func (k *KeyMap) Actions() []string {
+	var names []string
+	for _, a := range k.actions() {
+		names = append(names, a.name)
+	}
+	return names
+}
+
+// Customize rebinds the actions of overrides, keyed by action name. Nothing is changed
+// when an action is unknown, a key is empty or a key is bound to several actions.
+func (k *KeyMap) Customize(overrides map[string][]string) error {
+	actions := k.actions()
+	known := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		known[a.name] = true
+	}
+	for name, bound := range overrides {
+		if !known[name] {
+			return fmt.Errorf("unknown key action '%s', expected one of: %s", name, strings.Join(k.Actions(), ", "))
+		}
+		if len(bound) == 0 {
+			return fmt.Errorf("no key bound to action '%s'", name)
+		}
+		for _, keyName := range bound {
+			if strings.TrimSpace(keyName) == "" {
+				return fmt.Errorf("empty key bound to action '%s'", name)
+			}
+		}
+	}
+
+	// Every key must trigger a single action, customized or not
+	owners := make(map[string][]string)
+	for _, a := range actions {
+		bound, ok := overrides[a.name]
+		if !ok {
+			bound = a.binding.Keys()
+		}
+		for _, keyName := range bound {
+			if !slices.Contains(owners[keyName], a.name) {
+				owners[keyName] = append(owners[keyName], a.name)
+			}
+		}
+	}
+	var conflicts []string
+	for keyName, names := range owners {
+		if len(names) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("'%s' is bound to %s", keyName, strings.Join(names, " and ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("conflicting key bindings: %s", strings.Join(conflicts, ", "))
+	}
+
+	for _, a := range actions {
+		bound, ok := overrides[a.name]
+		if !ok {
+			continue
+		}
+		a.binding.SetKeys(bound...)
+		a.binding.SetHelp(helpKeys(bound), a.binding.Help().Desc)
+	}
+	return nil
+}
+
+// helpKeys formats keys the way the help shows them, e.g. "↑/k"
+func helpKeys(bound []string) string {
+	symbols := map[string]string{
+		"up":    "↑",
+		"down":  "↓",
+		"left":  "←",
+		"right": "→",
+	}
+	labels := make([]string, len(bound))
+	for i, keyName := range bound {
+		if symbol, ok := symbols[keyName]; ok {
+			keyName = symbol
+		}
+		labels[i] = keyName
+	}
+	return strings.Join(labels, "/")
+}
@@ -10,6 +10,20 @@ type KeyMap struct {
 	Return     key.Binding
 	OpenGitHub key.Binding
 	Help       key.Binding
+	Find       key.Binding
+	Sort       key.Binding
+	SortOrder  key.Binding
+	Branch     key.Binding
+	Retry      key.Binding
+	ErrorLog   key.Binding
+	Theme      key.Binding
+	Group      key.Binding
+	Add        key.Binding
+	Remove     key.Binding
+	Pin        key.Binding
+	MoveUp     key.Binding
+	MoveDown   key.Binding
+	Hidden     key.Binding
 }
 
 var Keys = &KeyMap{
@@ -41,6 +55,62 @@ var Keys = &KeyMap{
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
 	),
+	Find: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "find"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort by next column"),
+	),
+	SortOrder: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "reverse sort"),
+	),
+	Branch: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "toggle branch filter"),
+	),
+	Retry: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "retry failed row"),
+	),
+	ErrorLog: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "error log"),
+	),
+	Theme: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "switch theme"),
+	),
+	Group: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "switch group"),
+	),
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "add repository"),
+	),
+	Remove: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "remove repository"),
+	),
+	Pin: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pin/unpin"),
+	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "move pinned up"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "move pinned down"),
+	),
+	Hidden: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "toggle hidden workflows"),
+	),
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -50,7 +120,11 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Select},
-		{k.OpenGitHub, k.Return},
-		{k.Help, k.Quit},
+		{k.OpenGitHub, k.Return, k.Find},
+		{k.Sort, k.SortOrder, k.Branch},
+		{k.Retry, k.ErrorLog, k.Theme},
+		{k.Add, k.Remove, k.Group},
+		{k.Pin, k.MoveUp, k.MoveDown},
+		{k.Hidden, k.Help, k.Quit},
 	}
 }
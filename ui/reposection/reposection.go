@@ -1,10 +1,15 @@
 package reposection
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cpaluszek/gh-ci/config"
 	"github.com/cpaluszek/gh-ci/github"
 	"github.com/cpaluszek/gh-ci/ui/commands"
 	"github.com/cpaluszek/gh-ci/ui/components/table"
@@ -12,11 +17,40 @@ import (
 	"github.com/cpaluszek/gh-ci/ui/context"
 	"github.com/cpaluszek/gh-ci/ui/keys"
 	"github.com/cpaluszek/gh-ci/ui/section"
+	"github.com/cpaluszek/gh-ci/ui/utils"
 )
 
 type Model struct {
 	section.BaseModel
 	repos []*github.Repository
+	// rows holds the group headers and the visible repositories in display order
+	rows []github.RowData
+	// loading holds the lowercased full names of the repositories still being fetched
+	loading map[string]bool
+	// collapsed holds the groups whose repositories are hidden
+	collapsed map[string]bool
+	// removing is the repository removed from the config when the remove key is pressed again
+	removing string
+}
+
+// ungrouped is the header of the repositories outside of every group
+const ungrouped = "ungrouped"
+
+// groupHeader is the row heading the repositories of a group, the ungrouped ones have an empty name
+type groupHeader struct {
+	name  string
+	count int
+}
+
+func (h groupHeader) GetName() string {
+	if h.name == "" {
+		return ungrouped
+	}
+	return h.name
+}
+
+func (h groupHeader) GetURL() string {
+	return ""
 }
 
 func NewModel(ctx *context.Context) Model {
@@ -50,12 +84,18 @@ func NewModel(ctx *context.Context) Model {
 				Grow:  false,
 			},
 		},
+		table.Sort{Column: 4, Desc: true},
 	)
 
-	return Model{
+	m := Model{
 		BaseModel: base,
 		repos:     nil,
+		loading:   make(map[string]bool),
+		collapsed: make(map[string]bool),
 	}
+	// Repositories are fetched as soon as the client is ready
+	m.SetIsLoading(true)
+	return m
 }
 
 func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
@@ -65,25 +105,125 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	case commands.RepositoriesMsg:
 		m.repos = msg.Repositories
 		m.SetIsLoading(false)
-		m.Table.SetRows(m.BuildRows())
+		m.loading = make(map[string]bool)
+		m.Table.SetRowsLoading(false)
+		m.sortRows()
+		cmds = append(cmds, commands.SectionChanged)
+
+	case commands.RepositoriesLoadingMsg:
+		m.repos = msg.Repositories
+		m.SetIsLoading(false)
+		m.addLoading(msg.Names)
+		m.sortRows()
+		cmds = append(cmds, m.Table.StartLoadingSpinner(), commands.SectionChanged)
+
+	case commands.ConfigChangedMsg:
+		if msg.Config != nil {
+			m.sortRows()
+		}
+
+	case commands.RepositoryMsg:
+		if !slices.Contains(m.repos, msg.Repository) {
+			m.repos = append(m.repos, msg.Repository)
+		}
+		delete(m.loading, strings.ToLower(msg.Name))
+		m.Table.SetRowsLoading(len(m.loading) > 0)
+		m.sortRows()
 		cmds = append(cmds, commands.SectionChanged)
 
 	case tea.KeyMsg:
+		removing := m.removing
+		m.removing = ""
+		if m.UpdateSort(msg) {
+			m.sortRows()
+			return m, commands.SectionChanged
+		}
+
 		switch  {
 		case key.Matches(msg, keys.Keys.OpenGitHub):
-			if m.repos == nil {
+			repo, ok := m.GetCurrentRow().(*github.Repository)
+			if !ok || repo.URL == "" {
 				return m, nil
 			}
-			currentIndex := m.Table.GetCurrItem()
-			if currentIndex < 0 || currentIndex >= len(m.repos) {
+
+			return m, commands.OpenBrowser(repo.URL)
+
+		case key.Matches(msg, keys.Keys.Select):
+			header, ok := m.GetCurrentRow().(groupHeader)
+			if !ok {
 				return m, nil
 			}
-			url := m.repos[currentIndex].URL
-			if url == "" {
+			m.collapsed[header.name] = !m.collapsed[header.name]
+			m.sortRows()
+			return m, commands.SectionChanged
+
+		case key.Matches(msg, keys.Keys.Group):
+			return m, m.switchGroup()
+
+		case key.Matches(msg, keys.Keys.Remove):
+			repo, ok := m.GetCurrentRow().(*github.Repository)
+			if !ok {
 				return m, nil
 			}
+			if !m.Ctx.EditableConfig {
+				return m, commands.Toast(commands.ToastWarning, "Repositories can only be removed from the config file")
+			}
+			if removing != repo.FullName {
+				m.removing = repo.FullName
+				return m, commands.Toast(commands.ToastWarning,
+					"Press "+keys.Keys.Remove.Help().Key+" again to remove "+repo.FullName+" from the config")
+			}
+			return m, commands.EditConfig(m.Ctx.Profile, "remove "+repo.FullName, func() (string, error) {
+				return "Removed " + repo.FullName, config.RemoveRepository(repo.FullName)
+			})
 
-			return m, commands.OpenBrowser(url)
+		case key.Matches(msg, keys.Keys.Pin):
+			repo, ok := m.GetCurrentRow().(*github.Repository)
+			if !ok {
+				return m, nil
+			}
+			pinned := slices.Clone(m.Ctx.Config.Github.Pinned)
+			done := "Unpinned " + repo.FullName
+			if i := m.Ctx.Config.Github.PinIndex(repo.FullName); i >= 0 {
+				pinned = slices.Delete(pinned, i, i+1)
+			} else {
+				pinned = append(pinned, repo.FullName)
+				done = "Pinned " + repo.FullName
+			}
+			return m, m.savePinned(pinned, done)
+
+		case key.Matches(msg, keys.Keys.MoveUp), key.Matches(msg, keys.Keys.MoveDown):
+			repo, ok := m.GetCurrentRow().(*github.Repository)
+			if !ok {
+				return m, nil
+			}
+			pinned := slices.Clone(m.Ctx.Config.Github.Pinned)
+			i := m.Ctx.Config.Github.PinIndex(repo.FullName)
+			if i < 0 {
+				return m, commands.Toast(commands.ToastInfo, "Only pinned repositories can be moved")
+			}
+			j, done := i+1, "Moved "+repo.FullName+" down"
+			if key.Matches(msg, keys.Keys.MoveUp) {
+				j, done = i-1, "Moved "+repo.FullName+" up"
+			}
+			if j < 0 || j >= len(pinned) {
+				return m, nil
+			}
+			pinned[i], pinned[j] = pinned[j], pinned[i]
+			return m, m.savePinned(pinned, done)
+
+		case key.Matches(msg, keys.Keys.Retry):
+			repo, ok := m.GetCurrentRow().(*github.Repository)
+			if !ok || repo.Error == nil || m.Ctx.Client == nil || m.loading[strings.ToLower(repo.FullName)] {
+				return m, nil
+			}
+			m.loading[strings.ToLower(repo.FullName)] = true
+			m.Table.SetRowsLoading(true)
+			m.refreshRows()
+			return m, tea.Batch(
+				m.Table.StartLoadingSpinner(),
+				commands.RetryRepository(m.Ctx.Client, m.Ctx.FetchConfig(), repo.FullName),
+			)
 		}
 	}
 
@@ -92,13 +232,162 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	if cmd != nil {
 		cmds = append(cmds, cmd)
 	}
+	if _, ok := msg.(spinner.TickMsg); ok && len(m.loading) > 0 {
+		m.refreshRows()
+	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// savePinned writes the pinned repositories to the config file, the rows are ordered again once it is reloaded
+func (m *Model) savePinned(pinned []string, done string) tea.Cmd {
+	if !m.Ctx.EditableConfig {
+		return commands.Toast(commands.ToastWarning, "Repositories can only be pinned in the config file")
+	}
+	return commands.EditConfig(m.Ctx.Profile, "save the pinned repositories", func() (string, error) {
+		return done, config.SetPinned(pinned)
+	})
+}
+
+// addLoading marks the repositories being fetched
+func (m *Model) addLoading(names []string) {
+	for _, name := range names {
+		m.loading[strings.ToLower(name)] = true
+	}
+	m.Table.SetRowsLoading(len(m.loading) > 0)
+}
+
+// refreshRows rebuilds the rows, to animate the spinner of the repositories being fetched
+func (m *Model) refreshRows() {
+	m.Table.SetRows(m.BuildRows())
+}
+
+// sortRows orders the repositories by the table sort and rebuilds the rows, keeping the selection
+func (m *Model) sortRows() {
+	selected := m.GetCurrentRow()
+	table.SortItems(&m.Table, m.repos, sortKey)
+	// Pinned repositories come first, in the order of the config
+	cfg := m.Ctx.Config.Github
+	slices.SortStableFunc(m.repos, func(a, b *github.Repository) int {
+		return cmp.Compare(pinRank(cfg, a), pinRank(cfg, b))
+	})
+	m.layoutRows()
+	m.Table.SetRows(m.BuildRows())
+	if selected != nil {
+		m.selectVisibleRow(func(row github.RowData) bool {
+			if header, ok := selected.(groupHeader); ok {
+				other, ok := row.(groupHeader)
+				return ok && other.name == header.name
+			}
+			return row == selected
+		})
+	}
+}
+
+// layoutRows lists the repositories under the header of their group, in the order of m.repos.
+// Without groups in the config, the repositories are listed without headers.
+func (m *Model) layoutRows() {
+	cfg := m.Ctx.Config.Github
+	groups := cfg.GroupNames()
+	if !slices.Contains(groups, m.Ctx.Group) {
+		m.Ctx.Group = ""
+	}
+
+	m.rows = make([]github.RowData, 0, len(m.repos)+len(groups)+1)
+	if len(groups) == 0 {
+		for _, repo := range m.repos {
+			m.rows = append(m.rows, repo)
+		}
+		return
+	}
+
+	byGroup := make(map[string][]*github.Repository, len(groups)+1)
+	for _, repo := range m.repos {
+		group := cfg.GroupOf(repo.FullName)
+		byGroup[group] = append(byGroup[group], repo)
+	}
+	// The ungrouped repositories come last
+	for _, group := range append(groups, "") {
+		repos := byGroup[group]
+		if len(repos) == 0 || (m.Ctx.Group != "" && group != m.Ctx.Group) {
+			continue
+		}
+		m.rows = append(m.rows, groupHeader{name: group, count: len(repos)})
+		if m.collapsed[group] {
+			continue
+		}
+		for _, repo := range repos {
+			m.rows = append(m.rows, repo)
+		}
+	}
+}
+
+// switchGroup lists the group following the current one, then all groups again
+func (m *Model) switchGroup() tea.Cmd {
+	groups := m.Ctx.Config.Github.GroupNames()
+	if len(groups) == 0 {
+		return commands.Toast(commands.ToastInfo, "No groups in the config")
+	}
+
+	next := ""
+	if i := slices.Index(groups, m.Ctx.Group); i < len(groups)-1 {
+		next = groups[i+1]
+	}
+	m.Ctx.Group = next
+	m.collapsed[next] = false
+	m.sortRows()
+	m.Table.SetCurrItem(0)
+
+	label := "all"
+	if next != "" {
+		label = next
+	}
+	return tea.Batch(commands.Toast(commands.ToastInfo, "Group: "+label), commands.SectionChanged)
+}
+
+func pinRank(cfg config.GithubConfig, repo *github.Repository) int {
+	if i := cfg.PinIndex(repo.FullName); i >= 0 {
+		return i
+	}
+	return len(cfg.Pinned)
+}
+
+func sortKey(repo *github.Repository, column int) table.SortKey {
+	switch column {
+	case 0:
+		return repo.Name
+	case 1:
+		return repo.Language
+	case 2:
+		return repo.StargazerCount
+	case 3:
+		return repo.IsPrivate
+	case 4:
+		return repo.UpdatedAt
+	}
+	return nil
+}
+
 func (m Model) BuildRows() []table.Row {
 	var rows []table.Row
-	for _, repo := range m.repos {
+	for _, row := range m.rows {
+		repo, ok := row.(*github.Repository)
+		if !ok {
+			rows = append(rows, m.headerRow(row.(groupHeader)))
+			continue
+		}
+		name := repo.Name
+		if m.Ctx.Config.Github.PinIndex(repo.FullName) >= 0 {
+			name = m.Ctx.Theme.Symbols.Pinned + name
+		}
+		if m.loading[strings.ToLower(repo.FullName)] {
+			name = utils.CleanANSIEscapes(m.Table.SpinnerView()) + " " + name
+		} else if repo.Error != nil {
+			name += " · failed: " + github.ShortError(repo.Error)
+		}
+		if m.Ctx.Offline && !repo.FetchedAt.IsZero() {
+			name += " · stale " + utils.FormatTime(repo.FetchedAt)
+		}
 		language := repo.Language
 		stars := fmt.Sprintf("%d", repo.StargazerCount)
 		updated := repo.UpdatedAt.Format("Jan 2, 2006")
@@ -108,8 +397,13 @@ func (m Model) BuildRows() []table.Row {
 		} else {
 			visibility = "Public"
 		}
+		if repo.UpdatedAt.IsZero() {
+			// Placeholder of a repository not fetched yet
+			rows = append(rows, table.Row{name, "", "", "", ""})
+			continue
+		}
 		rows = append(rows, table.Row{
-			repo.Name,
+			name,
 			language,
 			stars,
 			visibility,
@@ -119,6 +413,14 @@ func (m Model) BuildRows() []table.Row {
 	return rows
 }
 
+func (m Model) headerRow(header groupHeader) table.Row {
+	symbol := m.Ctx.Theme.Symbols.GroupExpanded
+	if m.collapsed[header.name] {
+		symbol = m.Ctx.Theme.Symbols.GroupCollapsed
+	}
+	return table.Row{fmt.Sprintf("%s%s (%d)", symbol, header.GetName(), header.count), "", "", "", ""}
+}
+
 func (m *Model) GetDimensions() constants.Dimensions {
 	return constants.Dimensions{
 		Width:  m.Ctx.MainContentWidth,
@@ -127,7 +429,7 @@ func (m *Model) GetDimensions() constants.Dimensions {
 }
 
 func (m *Model) NumRows() int {
-	return len(m.repos)
+	return len(m.rows)
 }
 
 func (m *Model) SetIsLoading(val bool) {
@@ -149,15 +451,51 @@ func (m *Model) Fetch() []tea.Cmd {
 
 	var cmds []tea.Cmd
 	tableCmd := m.Table.StartLoadingSpinner()
-	fetchCmd := commands.FetchRepositories(m.Ctx.Client, m.Ctx.Config.Github.Repositories)
+	// Paint the previous session right away, then revalidate it
+	fetchCmd := tea.Sequence(
+		commands.LoadSnapshot(m.Ctx.Config.Github),
+		commands.StreamRepositories(m.Ctx.Client, m.Ctx.FetchConfig()),
+	)
 	cmds = append(cmds, tableCmd, fetchCmd)
 	m.SetIsLoading(true)
 	return cmds
 }
 
+// SelectRow selects the first repository matching, showing its group when it is hidden
+func (m *Model) SelectRow(match func(row github.RowData) bool) bool {
+	if m.selectVisibleRow(match) {
+		return true
+	}
+	for _, repo := range m.repos {
+		if !match(repo) {
+			continue
+		}
+		group := m.Ctx.Config.Github.GroupOf(repo.FullName)
+		if m.Ctx.Group != group {
+			m.Ctx.Group = ""
+		}
+		m.collapsed[group] = false
+		m.layoutRows()
+		m.Table.SetRows(m.BuildRows())
+		return m.selectVisibleRow(match)
+	}
+	return false
+}
+
+func (m *Model) selectVisibleRow(match func(row github.RowData) bool) bool {
+	for i, row := range m.rows {
+		if match(row) {
+			m.Table.SetCurrItem(i)
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) GetCurrentRow() github.RowData {
-	if len(m.repos) == 0 {
+	current := m.Table.GetCurrItem()
+	if current < 0 || current >= len(m.rows) {
 		return nil
 	}
-	return m.repos[m.Table.GetCurrItem()]
+	return m.rows[current]
 }
@@ -1,6 +1,8 @@
 package runsection
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cpaluszek/gh-ci/github"
@@ -37,7 +39,9 @@ func NewModel(ctx *context.Context) Model {
 				Width: 12,
 				Grow:  false,
 			},
-		})
+		},
+		table.Unsorted,
+	)
 
 	return Model{
 		BaseModel: base,
@@ -51,11 +55,16 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	switch msg := msg.(type) {
 	case commands.WorkflowRunMsg:
 		m.Runs = msg.RunWithJobs
-		m.Table.SetRows(m.BuildRows())
+		m.sortRows()
 		m.Table.FirstItem()
 		cmds = append(cmds, commands.SectionChanged)
 
 	case tea.KeyMsg:
+		if m.UpdateSort(msg) {
+			m.sortRows()
+			return m, commands.SectionChanged
+		}
+
 		switch  {
 		case key.Matches(msg, keys.Keys.OpenGitHub):
 			if m.Runs == nil {
@@ -82,6 +91,39 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// sortRows orders the jobs by the table sort and rebuilds the rows, keeping the selection
+func (m *Model) sortRows() {
+	if m.Runs == nil {
+		return
+	}
+	selected := m.GetCurrentRow()
+	table.SortItems(&m.Table, m.Runs.Jobs, sortKey)
+	m.Table.SetRows(m.BuildRows())
+	if selected != nil {
+		m.SelectRow(func(row github.RowData) bool {
+			return row == selected
+		})
+	}
+}
+
+func sortKey(job *github.Job, column int) table.SortKey {
+	switch column {
+	case 0:
+		return job.Name
+	case 1:
+		if job.Status == "completed" {
+			return job.Conclusion
+		}
+		return job.Status
+	case 2:
+		if job.CompletedAt.After(job.StartedAt) {
+			return job.CompletedAt.Sub(job.StartedAt)
+		}
+		return time.Since(job.StartedAt)
+	}
+	return nil
+}
+
 func (m Model) BuildRows() []table.Row {
 	if m.Runs == nil {
 		return nil
@@ -117,6 +159,19 @@ func (m *Model) Fetch() []tea.Cmd {
 	return nil
 }
 
+func (m *Model) SelectRow(match func(row github.RowData) bool) bool {
+	if m.Runs == nil {
+		return false
+	}
+	for i, job := range m.Runs.Jobs {
+		if match(job) {
+			m.Table.SetCurrItem(i)
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) GetCurrentRow() github.RowData {
 	if m == nil || m.Runs == nil || len(m.Runs.Jobs) == 0 {
 		return nil
@@ -1,12 +1,17 @@
 package section
 
 import (
+	"log"
+
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/config"
 	"github.com/cpaluszek/gh-ci/github"
 	"github.com/cpaluszek/gh-ci/ui/components/table"
 	"github.com/cpaluszek/gh-ci/ui/constants"
 	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/cpaluszek/gh-ci/ui/keys"
 )
 
 type BaseModel struct {
@@ -37,6 +42,8 @@ type Table interface {
 	BuildRows() []table.Row
 	GetIsLoading() bool
 	SetIsLoading(val bool)
+	SelectRow(match func(row github.RowData) bool) bool
+	SetRows(rows []table.Row)
 	// TODO: if not all section implement this, remove it
 	Fetch() []tea.Cmd
 }
@@ -45,6 +52,7 @@ func NewModel(
 	ctx *context.Context,
 	title string,
 	columns []table.Column,
+	defaultSort table.Sort,
 ) BaseModel {
 	m := BaseModel{
 		Title:   title,
@@ -62,10 +70,66 @@ func NewModel(
 		nil,
 		false,
 	)
+	m.Table.SetSort(m.loadSort(defaultSort))
 
 	return m
 }
 
+// UpdateSort applies the sort key bindings to the table and persists the result,
+// reporting whether the sort changed
+func (m *BaseModel) UpdateSort(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, keys.Keys.Sort):
+		m.Table.CycleSortColumn()
+	case key.Matches(msg, keys.Keys.SortOrder):
+		m.Table.ToggleSortOrder()
+	default:
+		return false
+	}
+
+	m.saveSort(m.Table.GetSort())
+	return true
+}
+
+// loadSort returns the sort saved in a previous session
+func (m *BaseModel) loadSort(defaultSort table.Sort) table.Sort {
+	state, err := config.LoadState()
+	if err != nil {
+		log.Printf("failed to load state: %v", err)
+		return defaultSort
+	}
+	saved, found := state.Sorts[m.Title]
+	if !found {
+		return defaultSort
+	}
+	if saved.Column == "" {
+		return table.Unsorted
+	}
+
+	for i, column := range m.Columns {
+		if column.Title == saved.Column {
+			return table.Sort{Column: i, Desc: saved.Desc}
+		}
+	}
+	return defaultSort
+}
+
+func (m *BaseModel) saveSort(s table.Sort) {
+	var saved config.SortState
+	if s.IsSorted() {
+		saved = config.SortState{Column: m.Columns[s.Column].Title, Desc: s.Desc}
+	}
+	err := config.UpdateState(func(state *config.State) {
+		if state.Sorts == nil {
+			state.Sorts = make(map[string]config.SortState)
+		}
+		state.Sorts[m.Title] = saved
+	})
+	if err != nil {
+		log.Printf("failed to save sort preference: %v", err)
+	}
+}
+
 func (m *BaseModel) NextRow() int {
 	return m.Table.NextItem()
 }
@@ -78,6 +142,11 @@ func (m *BaseModel) CurrRow() int {
 	return m.Table.GetCurrItem()
 }
 
+// SetRows replaces the rendered rows of the table, e.g. to apply a new theme
+func (m *BaseModel) SetRows(rows []table.Row) {
+	m.Table.SetRows(rows)
+}
+
 func (m *BaseModel) GetIsLoading() bool {
 	return m.IsLoading
 }
@@ -0,0 +1,299 @@
+package setup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/cpaluszek/gh-ci/ui/styles"
+	"github.com/cpaluszek/gh-ci/ui/utils"
+	"github.com/sahilm/fuzzy"
+)
+
+// reservedLines are the lines around the list: title, filter, separator, more and help
+const reservedLines = 7
+
+type repositoriesMsg struct {
+	repos []*github.Repository
+	err   error
+}
+
+type repositories []*github.Repository
+
+func (r repositories) String(i int) string {
+	return r[i].FullName
+}
+
+func (r repositories) Len() int {
+	return len(r)
+}
+
+// Model lists the repositories of the authenticated user having workflows and lets the user
+// pick those written to the first config file
+type Model struct {
+	ctx      *context.Context
+	client   *github.Client
+	input    textinput.Model
+	spinner  spinner.Model
+	repos    repositories
+	matches  fuzzy.Matches
+	cursor   int
+	selected map[string]bool
+	loading  bool
+	err      error
+	// Canceled is set when the user quits without saving
+	Canceled bool
+}
+
+func NewModel(client *github.Client) Model {
+	theme := styles.DefaultTheme
+	s := styles.BuildStyles(*theme)
+
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+	sp.Style = s.InProgress
+
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Filter repositories"
+	input.Focus()
+
+	return Model{
+		ctx: &context.Context{
+			Theme:  theme,
+			Styles: &s,
+		},
+		client:   client,
+		input:    input,
+		spinner:  sp,
+		selected: make(map[string]bool),
+		loading:  true,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, textinput.Blink, m.fetch())
+}
+
+func (m Model) fetch() tea.Cmd {
+	return func() tea.Msg {
+		repos, err := m.client.FetchAccessibleRepositories()
+		if err != nil {
+			return repositoriesMsg{err: err}
+		}
+
+		names := make([]string, len(repos))
+		for i, repo := range repos {
+			names[i] = repo.FullName
+		}
+		filtered, err := m.client.FilterWithWorkflows(names)
+		if err != nil {
+			// Offer every repository rather than hiding those that could not be checked
+			return repositoriesMsg{repos: repos}
+		}
+		withWorkflows := make(map[string]bool)
+		for _, name := range filtered {
+			withWorkflows[name] = true
+		}
+
+		var kept []*github.Repository
+		for _, repo := range repos {
+			if withWorkflows[repo.FullName] {
+				kept = append(kept, repo)
+			}
+		}
+		return repositoriesMsg{repos: kept}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.ctx.ScreenWidth = msg.Width
+		m.ctx.ScreenHeight = msg.Height
+		return m, nil
+
+	case repositoriesMsg:
+		m.loading = false
+		m.err = msg.err
+		m.repos = msg.repos
+		m.filter()
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC:
+			m.Canceled = true
+			return m, tea.Quit
+		case tea.KeyEnter:
+			if len(m.selected) == 0 {
+				return m, nil
+			}
+			return m, tea.Quit
+		case tea.KeySpace, tea.KeyTab:
+			if m.cursor < len(m.matches) {
+				name := m.repos[m.matches[m.cursor].Index].FullName
+				if m.selected[name] {
+					delete(m.selected, name)
+				} else {
+					m.selected[name] = true
+				}
+			}
+			return m, nil
+		case tea.KeyCtrlA:
+			m.toggleMatches()
+			return m, nil
+		case tea.KeyUp, tea.KeyCtrlP, tea.KeyCtrlK:
+			m.cursor = max(m.cursor-1, 0)
+			return m, nil
+		case tea.KeyDown, tea.KeyCtrlN, tea.KeyCtrlJ:
+			m.cursor = min(m.cursor+1, max(len(m.matches)-1, 0))
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.filter()
+	}
+	return m, cmd
+}
+
+// toggleMatches selects every repository matching the filter, or unselects them when they all are
+func (m *Model) toggleMatches() {
+	all := true
+	for _, match := range m.matches {
+		if !m.selected[m.repos[match.Index].FullName] {
+			all = false
+			break
+		}
+	}
+	for _, match := range m.matches {
+		name := m.repos[match.Index].FullName
+		if all {
+			delete(m.selected, name)
+		} else {
+			m.selected[name] = true
+		}
+	}
+}
+
+func (m *Model) filter() {
+	m.cursor = 0
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" {
+		m.matches = make(fuzzy.Matches, len(m.repos))
+		for i := range m.repos {
+			m.matches[i] = fuzzy.Match{Str: m.repos[i].FullName, Index: i}
+		}
+		return
+	}
+	m.matches = fuzzy.FindFrom(query, m.repos)
+}
+
+// Selected returns the full names of the selected repositories, most recently pushed first
+func (m Model) Selected() []string {
+	var names []string
+	for _, repo := range m.repos {
+		if m.selected[repo.FullName] {
+			names = append(names, repo.FullName)
+		}
+	}
+	return names
+}
+
+func (m Model) Err() error {
+	return m.err
+}
+
+func (m Model) View() string {
+	lines := []string{m.ctx.Styles.Title.Render("Welcome to gh-ci! Select the repositories to follow"), ""}
+
+	switch {
+	case m.loading:
+		lines = append(lines, m.spinner.View()+" "+m.ctx.Styles.Skipped.Render("Listing your repositories with workflows…"))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...) + "\n"
+	case m.err != nil:
+		lines = append(lines,
+			m.ctx.Styles.Error.Render("Error: "+m.err.Error()),
+			m.ctx.Styles.Skipped.Render("Press esc to quit"))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...) + "\n"
+	}
+
+	width := max(m.ctx.ScreenWidth-2, 20)
+	m.input.Width = width - 4
+	lines = append(lines, m.input.View(), m.ctx.Styles.Header.Width(width).Render(""))
+
+	if len(m.matches) == 0 {
+		lines = append(lines, m.ctx.Styles.Skipped.Render("No repositories with workflows"))
+	}
+
+	numResults := max(m.ctx.ScreenHeight-reservedLines, 1)
+	start := max(0, m.cursor-numResults+1)
+	end := min(len(m.matches), start+numResults)
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderMatch(m.matches[i], i == m.cursor, width))
+	}
+	if len(m.matches) > end {
+		lines = append(lines, m.ctx.Styles.Skipped.Render(fmt.Sprintf("+ %d more", len(m.matches)-end)))
+	}
+
+	lines = append(lines, "", m.ctx.Styles.Skipped.Render(fmt.Sprintf(
+		"%d selected · space select · ctrl+a select all matches · enter save and start · esc quit",
+		len(m.selected))))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...) + "\n"
+}
+
+func (m Model) renderMatch(match fuzzy.Match, current bool, width int) string {
+	repo := m.repos[match.Index]
+
+	style := m.ctx.Styles.Row
+	if current {
+		style = m.ctx.Styles.SelectedRow
+	}
+	highlight := style.Inherit(m.ctx.Styles.Match)
+
+	matched := make(map[int]bool, len(match.MatchedIndexes))
+	for _, index := range match.MatchedIndexes {
+		matched[index] = true
+	}
+
+	checkbox := "[ ] "
+	if m.selected[repo.FullName] {
+		checkbox = "[x] "
+	}
+	var text strings.Builder
+	text.WriteString(style.Render(checkbox))
+	for i, r := range repo.FullName {
+		if matched[i] {
+			text.WriteString(highlight.Render(string(r)))
+		} else {
+			text.WriteString(style.Render(string(r)))
+		}
+	}
+
+	var details []string
+	if repo.Language != "" {
+		details = append(details, repo.Language)
+	}
+	if !repo.UpdatedAt.IsZero() {
+		details = append(details, "updated "+utils.FormatTime(repo.UpdatedAt))
+	}
+	text.WriteString(style.Inherit(m.ctx.Styles.Skipped).Render("  " + strings.Join(details, " · ")))
+	return style.Width(width).MaxWidth(width).Render(text.String())
+}
@@ -3,6 +3,7 @@ package stepsection
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -48,12 +49,16 @@ func NewModel(ctx *context.Context) Model {
 				Grow:  false,
 			},
 		},
+		table.Unsorted,
 	)
 
 	logVp := viewport.New(
 		ctx.MainContentWidth,
 		ctx.MainContentHeight-2,
 	)
+	// Scroll with the configured keys
+	logVp.KeyMap.Up = keys.Keys.Up
+	logVp.KeyMap.Down = keys.Keys.Down
 
 	m := Model{
 		BaseModel:    base,
@@ -79,10 +84,15 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	case commands.LogsMsg:
 		m.steps = msg.Steps
 		m.SetIsLoading(false)
-		m.Table.SetRows(m.BuildRows())
+		m.sortRows()
 		cmds = append(cmds, commands.SectionChanged)
 
 	case tea.KeyMsg:
+		if !m.inLogMode && m.UpdateSort(msg) {
+			m.sortRows()
+			return m, commands.SectionChanged
+		}
+
 		switch {
 		case key.Matches(msg, keys.Keys.Select):
 			currentIndex := m.Table.GetCurrItem()
@@ -136,6 +146,25 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// sortRows orders the steps by the table sort and rebuilds the rows
+func (m *Model) sortRows() {
+	table.SortItems(&m.Table, m.steps, sortKey)
+	m.Table.SetRows(m.BuildRows())
+}
+
+func sortKey(step github.Steplog, column int) table.SortKey {
+	switch column {
+	case 0:
+		return step.Title
+	case 1:
+		return step.Status
+	case 2:
+		duration, _ := time.ParseDuration(step.Duration)
+		return duration
+	}
+	return nil
+}
+
 func (m Model) BuildRows() []table.Row {
 	var rows []table.Row
 
@@ -276,6 +305,10 @@ func (m *Model) Fetch() []tea.Cmd {
 	return cmds
 }
 
+func (m *Model) SelectRow(match func(row github.RowData) bool) bool {
+	return false
+}
+
 func (m *Model) GetCurrentRow() github.RowData {
 	return nil
 }
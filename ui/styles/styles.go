@@ -3,6 +3,7 @@ package styles
 import (
 	bbhelp "github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 type Styles struct {
@@ -34,6 +35,9 @@ type Styles struct {
 	Tag              lipgloss.Style
 	WebHook          lipgloss.Style
 	Fork             lipgloss.Style
+	Finder           lipgloss.Style
+	Match            lipgloss.Style
+	Toast            lipgloss.Style
 }
 
 func BuildStyles(theme Theme) Styles {
@@ -73,6 +77,10 @@ func BuildStyles(theme Theme) Styles {
 	s.TableHeader = lipgloss.NewStyle().Bold(true)
 
 	s.SelectedRow = lipgloss.NewStyle().Background(theme.Colors.SelectedBackground).Foreground(theme.Colors.SelectedText)
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		// Colors are disabled, e.g. by NO_COLOR, the selection is still visible in reverse video
+		s.SelectedRow = s.SelectedRow.Reverse(true)
+	}
 
 	s.SectionContainer = lipgloss.NewStyle().Padding(0, 1)
 
@@ -119,5 +127,17 @@ func BuildStyles(theme Theme) Styles {
 
 	s.Fork = lipgloss.NewStyle().Foreground(theme.Colors.Fork).Bold(true)
 
+	s.Finder = lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Colors.SecondaryBorder)
+
+	s.Match = lipgloss.NewStyle().Foreground(theme.Colors.Warning).Bold(true)
+
+	s.Toast = lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		Foreground(theme.Colors.Primary)
+
 	return s
 }
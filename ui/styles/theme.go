@@ -24,6 +24,12 @@ type Symbols struct {
 	JobSuccess, JobFailure, JobCanceled, JobSkipped, JobInProgress string
 	// Event symbols
 	PullRequest, Push, Schedule, Tag, Webhook, Fork, Deployment, Play, Issue string
+	// Table symbols
+	SortAscending, SortDescending string
+	// Group header symbols
+	GroupExpanded, GroupCollapsed string
+	// Marker of the pinned rows
+	Pinned string
 }
 
 var DefaultTheme = &Theme{
@@ -52,26 +58,31 @@ var DefaultTheme = &Theme{
 		Fork:               lipgloss.AdaptiveColor{Light: "008", Dark: "008"},
 	},
 	Symbols: Symbols{
-		Success:       "󰄬 ",
-		Failure:       "󰅚 ",
-		Canceled:      "󰔛 ",
-		Skipped:       "󰒭 ",
-		Neutral:       "󰘿 ",
-		InProgress:    "󰑮 ",
-		Queued:        "󰥔 ",
-		JobSuccess:    "󰄯 ",
-		JobFailure:    "󰅙 ",
-		JobCanceled:   " ",
-		JobSkipped:    " ",
-		JobInProgress: "󱥸 ",
-		PullRequest:   " ",
-		Push:          " ",
-		Schedule:      "󰃰 ",
-		Tag:           " ",
-		Webhook:       "󰛢 ",
-		Fork:          " ",
-		Deployment:    "󱓞 ",
-		Play:          " ",
-		Issue:         " ",
+		Success:        "󰄬 ",
+		Failure:        "󰅚 ",
+		Canceled:       "󰔛 ",
+		Skipped:        "󰒭 ",
+		Neutral:        "󰘿 ",
+		InProgress:     "󰑮 ",
+		Queued:         "󰥔 ",
+		JobSuccess:     "󰄯 ",
+		JobFailure:     "󰅙 ",
+		JobCanceled:    " ",
+		JobSkipped:     " ",
+		JobInProgress:  "󱥸 ",
+		PullRequest:    " ",
+		Push:           " ",
+		Schedule:       "󰃰 ",
+		Tag:            " ",
+		Webhook:        "󰛢 ",
+		Fork:           " ",
+		Deployment:     "󱓞 ",
+		Play:           " ",
+		Issue:          " ",
+		SortAscending:  "▲",
+		SortDescending: "▼",
+		GroupExpanded:  "▾ ",
+		GroupCollapsed: "▸ ",
+		Pinned:         "󰐃 ",
 	},
 }
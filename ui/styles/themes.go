@@ -0,0 +1,266 @@
+package styles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultThemeName is the theme used when the config does not set one
+const DefaultThemeName = "default"
+
+const themeFileExt = ".yaml"
+
+var builtinThemes = []struct {
+	name  string
+	theme *Theme
+}{
+	{DefaultThemeName, DefaultTheme},
+	{"ascii", AsciiTheme},
+	{"high-contrast", HighContrastTheme},
+	{"light", LightTheme},
+}
+
+// AsciiTheme keeps the default colors with symbols that render without a Nerd Font
+var AsciiTheme = &Theme{
+	Colors: DefaultTheme.Colors,
+	Symbols: Symbols{
+		Success:        "✓ ",
+		Failure:        "✗ ",
+		Canceled:       "~ ",
+		Skipped:        "- ",
+		Neutral:        "o ",
+		InProgress:     "* ",
+		Queued:         ". ",
+		JobSuccess:     "✓",
+		JobFailure:     "✗",
+		JobCanceled:    "~",
+		JobSkipped:     "-",
+		JobInProgress:  "*",
+		PullRequest:    "R ",
+		Push:           "P ",
+		Schedule:       "S ",
+		Tag:            "T ",
+		Webhook:        "W ",
+		Fork:           "F ",
+		Deployment:     "D ",
+		Play:           "M ",
+		Issue:          "I ",
+		SortAscending:  "^",
+		SortDescending: "v",
+		GroupExpanded:  "- ",
+		GroupCollapsed: "+ ",
+		Pinned:         "@ ",
+	},
+}
+
+// HighContrastTheme uses the bright colors of the terminal palette
+var HighContrastTheme = &Theme{
+	Colors: Colors{
+		Primary:            lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		Secondary:          lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		Faint:              lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		PrimaryBorder:      lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		SecondaryBorder:    lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		SelectedBackground: lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		SelectedText:       lipgloss.AdaptiveColor{Light: "015", Dark: "000"},
+		Success:            lipgloss.AdaptiveColor{Light: "022", Dark: "010"},
+		Warning:            lipgloss.AdaptiveColor{Light: "094", Dark: "011"},
+		Error:              lipgloss.AdaptiveColor{Light: "124", Dark: "009"},
+		Info:               lipgloss.AdaptiveColor{Light: "019", Dark: "012"},
+		Progress:           lipgloss.AdaptiveColor{Light: "019", Dark: "014"},
+		Skipped:            lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		PullRequest:        lipgloss.AdaptiveColor{Light: "019", Dark: "012"},
+		Push:               lipgloss.AdaptiveColor{Light: "094", Dark: "011"},
+		Schedule:           lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+		Play:               lipgloss.AdaptiveColor{Light: "090", Dark: "013"},
+		Issue:              lipgloss.AdaptiveColor{Light: "124", Dark: "009"},
+		Deployment:         lipgloss.AdaptiveColor{Light: "022", Dark: "010"},
+		Tag:                lipgloss.AdaptiveColor{Light: "024", Dark: "014"},
+		WebHook:            lipgloss.AdaptiveColor{Light: "094", Dark: "011"},
+		Fork:               lipgloss.AdaptiveColor{Light: "000", Dark: "015"},
+	},
+	Symbols: DefaultTheme.Symbols,
+}
+
+// LightTheme is meant for light terminal backgrounds, whatever the detected background
+var LightTheme = &Theme{
+	Colors: Colors{
+		Primary:            lipgloss.AdaptiveColor{Light: "235", Dark: "235"},
+		Secondary:          lipgloss.AdaptiveColor{Light: "240", Dark: "240"},
+		Faint:              lipgloss.AdaptiveColor{Light: "244", Dark: "244"},
+		PrimaryBorder:      lipgloss.AdaptiveColor{Light: "248", Dark: "248"},
+		SecondaryBorder:    lipgloss.AdaptiveColor{Light: "250", Dark: "250"},
+		SelectedBackground: lipgloss.AdaptiveColor{Light: "153", Dark: "153"},
+		SelectedText:       lipgloss.AdaptiveColor{Light: "232", Dark: "232"},
+		Success:            lipgloss.AdaptiveColor{Light: "028", Dark: "028"},
+		Warning:            lipgloss.AdaptiveColor{Light: "130", Dark: "130"},
+		Error:              lipgloss.AdaptiveColor{Light: "160", Dark: "160"},
+		Info:               lipgloss.AdaptiveColor{Light: "025", Dark: "025"},
+		Progress:           lipgloss.AdaptiveColor{Light: "025", Dark: "025"},
+		Skipped:            lipgloss.AdaptiveColor{Light: "245", Dark: "245"},
+		PullRequest:        lipgloss.AdaptiveColor{Light: "025", Dark: "025"},
+		Push:               lipgloss.AdaptiveColor{Light: "130", Dark: "130"},
+		Schedule:           lipgloss.AdaptiveColor{Light: "240", Dark: "240"},
+		Play:               lipgloss.AdaptiveColor{Light: "091", Dark: "091"},
+		Issue:              lipgloss.AdaptiveColor{Light: "160", Dark: "160"},
+		Deployment:         lipgloss.AdaptiveColor{Light: "028", Dark: "028"},
+		Tag:                lipgloss.AdaptiveColor{Light: "030", Dark: "030"},
+		WebHook:            lipgloss.AdaptiveColor{Light: "130", Dark: "130"},
+		Fork:               lipgloss.AdaptiveColor{Light: "240", Dark: "240"},
+	},
+	Symbols: DefaultTheme.Symbols,
+}
+
+// themeFile is a theme defined in the themes directory, overriding the fields of its base theme
+type themeFile struct {
+	// Base is the theme the file starts from, the default theme when empty
+	Base    string                `yaml:"base"`
+	Colors  map[string]colorValue `yaml:"colors"`
+	Symbols map[string]string     `yaml:"symbols"`
+}
+
+// colorValue is either a single color or a 'light' and 'dark' pair
+type colorValue lipgloss.AdaptiveColor
+
+func (c *colorValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		c.Light, c.Dark = node.Value, node.Value
+		return nil
+	}
+	var pair struct {
+		Light string `yaml:"light"`
+		Dark  string `yaml:"dark"`
+	}
+	if err := node.Decode(&pair); err != nil {
+		return err
+	}
+	if pair.Light == "" || pair.Dark == "" {
+		return fmt.Errorf("line %d: a color needs both 'light' and 'dark' values", node.Line)
+	}
+	c.Light, c.Dark = pair.Light, pair.Dark
+	return nil
+}
+
+// ThemeNames returns the built-in themes followed by the themes of dir
+func ThemeNames(dir string) []string {
+	var names []string
+	for _, builtin := range builtinThemes {
+		names = append(names, builtin.name)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+themeFileExt))
+	var custom []string
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), themeFileExt)
+		if builtinTheme(name) == nil {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// LoadTheme returns the theme named name, from the YAML file of dir with that name if there
+// is one, or from the built-in themes. An empty name returns the default theme.
+func LoadTheme(name, dir string) (*Theme, error) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+
+	path := filepath.Join(dir, name+themeFileExt)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if theme := builtinTheme(name); theme != nil {
+			return theme, nil
+		}
+		return nil, fmt.Errorf("unknown theme '%s', expected one of: %s", name, strings.Join(ThemeNames(dir), ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme %s: %w", path, err)
+	}
+
+	var file themeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+	theme, err := file.apply()
+	if err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+func builtinTheme(name string) *Theme {
+	for _, builtin := range builtinThemes {
+		if builtin.name == name {
+			return builtin.theme
+		}
+	}
+	return nil
+}
+
+// apply returns a copy of the base theme with the fields of the file overridden
+func (f themeFile) apply() (*Theme, error) {
+	baseName := f.Base
+	if baseName == "" {
+		baseName = DefaultThemeName
+	}
+	base := builtinTheme(baseName)
+	if base == nil {
+		return nil, fmt.Errorf("unknown base theme '%s'", baseName)
+	}
+	theme := *base
+
+	colors := reflect.ValueOf(&theme.Colors).Elem()
+	for key, value := range f.Colors {
+		field, err := themeField(colors, key)
+		if err != nil {
+			return nil, fmt.Errorf("colors: %w", err)
+		}
+		field.Set(reflect.ValueOf(lipgloss.AdaptiveColor(value)))
+	}
+
+	symbols := reflect.ValueOf(&theme.Symbols).Elem()
+	for key, value := range f.Symbols {
+		field, err := themeField(symbols, key)
+		if err != nil {
+			return nil, fmt.Errorf("symbols: %w", err)
+		}
+		field.SetString(value)
+	}
+
+	return &theme, nil
+}
+
+// themeField returns the field of a Colors or Symbols struct named by a snake_case key
+func themeField(s reflect.Value, key string) (reflect.Value, error) {
+	name := strings.ReplaceAll(strings.ToLower(key), "_", "")
+	var names []string
+	for i := 0; i < s.NumField(); i++ {
+		fieldName := s.Type().Field(i).Name
+		if strings.ToLower(fieldName) == name {
+			return s.Field(i), nil
+		}
+		names = append(names, snakeCase(fieldName))
+	}
+	return reflect.Value{}, fmt.Errorf("unknown field '%s', expected one of: %s", key, strings.Join(names, ", "))
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
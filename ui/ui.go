@@ -1,17 +1,26 @@
 package ui
 
 import (
+	"fmt"
 	"log"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/cache"
 	"github.com/cpaluszek/gh-ci/config"
 	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/notify"
 	"github.com/cpaluszek/gh-ci/ui/commands"
+	"github.com/cpaluszek/gh-ci/ui/components/errorlog"
+	"github.com/cpaluszek/gh-ci/ui/components/finder"
 	"github.com/cpaluszek/gh-ci/ui/components/footer"
+	"github.com/cpaluszek/gh-ci/ui/components/prompt"
 	"github.com/cpaluszek/gh-ci/ui/components/sidebar"
+	"github.com/cpaluszek/gh-ci/ui/components/toast"
 	"github.com/cpaluszek/gh-ci/ui/constants"
 	"github.com/cpaluszek/gh-ci/ui/context"
 	"github.com/cpaluszek/gh-ci/ui/keys"
@@ -24,27 +33,81 @@ import (
 )
 
 type Model struct {
-	footer   footer.Model
-	ctx      *context.Context
-	repos    section.Section
-	worflows section.Section
-	run      section.Section
-	step     section.Section
-	sidebar  sidebar.Model
+	footer       footer.Model
+	ctx          *context.Context
+	repos        section.Section
+	worflows     section.Section
+	run          section.Section
+	step         section.Section
+	sidebar      sidebar.Model
+	finder       finder.Model
+	prompt       prompt.Model
+	toast        toast.Model
+	errorLog     errorlog.Model
+	repositories []*github.Repository
+	// startRepository is opened in the workflow view once repositories are loaded,
+	// or at startRun, of startAttempt if set, and startJob when they are set
+	startRepository string
+	startRun        int64
+	startAttempt    int
+	startJob        int64
+	// themeName is the name of the current theme, empty for the default one
+	themeName string
+	// notifier delivers the notifications of the runs completed between two fetches, nil when disabled
+	notifier notify.Backend
+	tracker  *notify.Tracker
+	// toastNotifications shows the notifications as toasts, in place of the terminal backend
+	toastNotifications bool
+	// pollID identifies the current polling loop, those started before a config reload stop
+	pollID int
 }
 
-func NewModel(cfg *config.Config) Model {
-	theme := styles.DefaultTheme
+// Options configures where the UI starts
+type Options struct {
+	// Repository is an 'owner/name' whose workflows are shown on startup
+	Repository string
+	// Branch filters the workflow runs, it can be toggled from the workflow view
+	Branch string
+	// RunID of the Repository opened in the run view, only this run is fetched
+	RunID int64
+	// Attempt of the run opened, its latest attempt when zero
+	Attempt int
+	// JobID of the run opened in the step view
+	JobID int64
+	// Theme of the UI, the default theme when nil
+	Theme *styles.Theme
+	// WatchConfig reloads the config file when it changes, and lets the UI edit it
+	WatchConfig bool
+	// Profile applied to the config, it is applied again when the config file is reloaded
+	Profile string
+}
+
+func NewModel(cfg *config.Config, opts Options) Model {
+	theme := opts.Theme
+	if theme == nil {
+		theme = styles.DefaultTheme
+	}
 	styles := styles.BuildStyles(*theme)
 	m := Model{
 		ctx: &context.Context{
-			ScreenWidth:  0,
-			ScreenHeight: 0,
-			Config:       cfg,
-			Theme:        theme,
-			Styles:       &styles,
+			ScreenWidth:    0,
+			ScreenHeight:   0,
+			Config:         cfg,
+			Theme:          theme,
+			Styles:         &styles,
+			Branch:         opts.Branch,
+			FilterByBranch: opts.Branch != "",
+			Profile:        opts.Profile,
+			EditableConfig: opts.WatchConfig,
 		},
+		startRepository: opts.Repository,
+		startRun:        opts.RunID,
+		startAttempt:    opts.Attempt,
+		startJob:        opts.JobID,
+		themeName:       cfg.Theme,
+		tracker:         notify.NewTracker(cfg.Notifications),
 	}
+	m.notifier, m.toastNotifications = notify.NewWithoutTerminal(cfg.Notifications)
 	f := footer.NewModel(m.ctx)
 	m.footer = f
 
@@ -58,21 +121,56 @@ func NewModel(cfg *config.Config) Model {
 	m.run = &r
 	sidebar := sidebar.NewModel(m.ctx)
 	m.sidebar = sidebar
+	m.finder = finder.NewModel(m.ctx)
+	m.prompt = prompt.NewModel(m.ctx)
+	m.toast = toast.NewModel(m.ctx)
+	m.errorLog = errorlog.NewModel(m.ctx)
 
 	return m
 }
 
 func (m Model) Init() tea.Cmd {
 	m.ctx.View = context.RepoView
-	return commands.InitClient()
+	cmds := []tea.Cmd{commands.InitClient(m.ctx.Config.Github), commands.PruneCache()}
+	if m.ctx.EditableConfig {
+		cmds = append(cmds, commands.WatchConfig(config.Watch(m.ctx.Profile)))
+	}
+	cmds = append(cmds, m.poll())
+	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
+
+	if _, ok := msg.(tea.KeyMsg); ok && m.finder.IsActive() {
+		m.finder, cmd = m.finder.Update(msg)
+		return m, cmd
+	}
+	if _, ok := msg.(tea.KeyMsg); ok && m.prompt.IsActive() {
+		m.prompt, cmd = m.prompt.Update(msg)
+		return m, cmd
+	}
+	if _, ok := msg.(tea.KeyMsg); ok && m.errorLog.IsActive() {
+		m.errorLog, cmd = m.errorLog.Update(msg)
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case key.Matches(msg, keys.Keys.Find):
+			return m, m.finder.Open(m.repositories)
+		case key.Matches(msg, keys.Keys.ErrorLog):
+			m.errorLog.Open()
+			m.ctx.Error = nil
+			return m, nil
+		case key.Matches(msg, keys.Keys.Theme):
+			return m, m.switchTheme()
+		case key.Matches(msg, keys.Keys.Add) && m.ctx.View == context.RepoView:
+			return m, m.openAddRepository()
+		case key.Matches(msg, keys.Keys.Hidden):
+			return m, m.toggleHidden()
 		case key.Matches(msg, keys.Keys.Quit):
 			m.footer, cmd = m.footer.Update(msg)
 			return m, cmd
@@ -85,11 +183,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Keys.Select):
 			switch m.ctx.View {
 			case context.RepoView:
-				repo := m.repos.GetCurrentRow()
+				repo, ok := m.repos.GetCurrentRow().(*github.Repository)
+				if !ok {
+					// Group headers are collapsed by the section
+					break
+				}
 				m.ctx.View = context.WorkflowView
 				return m, commands.GoToWorkflow(repo)
 			case context.WorkflowView:
-				workflowRun := m.worflows.GetCurrentRow()
+				workflowRun, ok := m.worflows.GetCurrentRow().(*github.WorkflowRun)
+				if !ok {
+					// Workflows whose runs failed to load have nothing to open
+					return m, nil
+				}
 				m.ctx.View = context.RunView
 				return m, commands.GoToRun(workflowRun)
 			case context.RunView:
@@ -125,14 +231,179 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case commands.ClientInitMsg:
 		m.ctx.Client = msg.Client
-		cmds = append(cmds, m.repos.Fetch()...)
+		if m.ctx.Offline {
+			// Keep browsing the snapshot until live data arrives
+			cmds = append(cmds, commands.RefreshRepositories(msg.Client, m.ctx.FetchConfig()))
+		} else if m.startRun != 0 {
+			cmds = append(cmds, commands.FetchRepositoryWithRun(msg.Client, m.startRepository, m.startRun, m.startAttempt))
+		} else {
+			cmds = append(cmds, m.repos.Fetch()...)
+		}
+
+	case commands.RepositoriesMsg:
+		// Known repositories are updated in place, so that sections keep their selection
+		msg.Repositories = github.MergeRepositories(m.repositories, msg.Repositories)
+		m.repositories = msg.Repositories
+		switch {
+		case m.ctx.Offline && !msg.Offline && !msg.Cached:
+			cmds = append(cmds, commands.Toast(commands.ToastSuccess, "Back online"))
+		case !m.ctx.Offline && msg.Offline:
+			cmds = append(cmds, commands.Toast(commands.ToastWarning, "GitHub unreachable, showing cached data"))
+		}
+		if !msg.Offline && !msg.Cached {
+			cmds = append(cmds, m.observe(msg.Repositories...))
+		}
+		m.ctx.Offline = msg.Offline
+		m.ctx.Refreshing = msg.Cached
+		if msg.Offline {
+			cmds = append(cmds, commands.Reconnect())
+		}
+
+		// Sections only receive messages while they are displayed
+		m.repos.UpdateContext(m.ctx)
+		_, cmd = m.repos.Update(msg)
+		cmds = append(cmds, cmd)
+		if m.startRepository != "" {
+			cmds = append(cmds, m.openStartRepository())
+			m.startRepository = ""
+			m.startRun = 0
+			m.startAttempt = 0
+			m.startJob = 0
+		} else if m.ctx.View == context.WorkflowView {
+			m.worflows.UpdateContext(m.ctx)
+			_, cmd = m.worflows.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case commands.RepositoriesLoadingMsg:
+		// List the repositories right away, they are filled in as they arrive
+		msg.Repositories = nil
+		for _, name := range msg.Names {
+			repo := github.FindRepository(m.repositories, name)
+			if repo == nil {
+				_, repoName, _ := strings.Cut(name, "/")
+				repo = &github.Repository{Name: repoName, FullName: name}
+				m.repositories = append(m.repositories, repo)
+			}
+			msg.Repositories = append(msg.Repositories, repo)
+		}
+		m.repos.UpdateContext(m.ctx)
+		_, cmd = m.repos.Update(msg)
+		cmds = append(cmds, cmd, msg.Next)
+		return m, tea.Batch(cmds...)
+
+	case commands.RepositoryMsg:
+		if err := msg.Repository.Error; err != nil {
+			m.errorLog.Add("fetch "+msg.Name, err)
+		}
+		if msg.Retry {
+			cmds = append(cmds, retryToast(msg.Name, msg.Repository.Error))
+		}
+		m.repositories, msg.Repository = github.MergeRepository(m.repositories, msg.Repository)
+		cmds = append(cmds, m.observe(msg.Repository))
+		m.repos.UpdateContext(m.ctx)
+		_, cmd = m.repos.Update(msg)
+		cmds = append(cmds, cmd, msg.Next)
+		if m.ctx.View == context.WorkflowView {
+			m.worflows.UpdateContext(m.ctx)
+			_, cmd = m.worflows.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case commands.WorkflowRefreshedMsg:
+		name := msg.Repository.FullName + " › " + msg.Workflow.Name
+		if err := msg.Workflow.Error; err != nil {
+			m.errorLog.Add("fetch the runs of "+name, err)
+		}
+		cmds = append(cmds, retryToast(name, msg.Workflow.Error))
+		// Replace the workflow in place, so that the sections holding the repository see it
+		for _, workflow := range msg.Repository.Workflows {
+			if workflow.ID == msg.Workflow.ID {
+				*workflow = *msg.Workflow
+				msg.Workflow = workflow
+				break
+			}
+		}
+		m.worflows.UpdateContext(m.ctx)
+		_, cmd = m.worflows.Update(msg)
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+
+	case commands.OfflineMsg:
+		log.Printf("GitHub unreachable, retrying in the background: %v", msg.Error)
+		m.errorLog.Add("reach GitHub", msg.Error)
+		if !m.ctx.Offline {
+			cmds = append(cmds, commands.Toast(commands.ToastWarning, "GitHub unreachable, retrying in the background"))
+		}
+		m.ctx.Offline = true
+		m.ctx.Refreshing = false
+		cmds = append(cmds, commands.Reconnect())
+
+	case commands.PollMsg:
+		if msg.ID != m.pollID {
+			break
+		}
+		if m.ctx.Client != nil && !m.ctx.Offline && !m.ctx.Refreshing {
+			cmds = append(cmds, commands.RefreshRepositories(m.ctx.Client, m.ctx.FetchConfig()))
+		}
+		cmds = append(cmds, m.poll())
+
+	case commands.ReconnectMsg:
+		if m.ctx.Client == nil {
+			cmds = append(cmds, commands.InitClient(m.ctx.Config.Github))
+		} else {
+			cmds = append(cmds, commands.RefreshRepositories(m.ctx.Client, m.ctx.FetchConfig()))
+		}
+
+	case commands.ConfigChangedMsg:
+		cmds = append(cmds, msg.Next)
+		if msg.Error != nil {
+			log.Printf("Invalid config, keeping the previous one: %v", msg.Error)
+			m.errorLog.Add("reload the config", msg.Error)
+			cmds = append(cmds, commands.Toast(commands.ToastError, "Invalid config, keeping the previous one"))
+			break
+		}
+		cmds = append(cmds, m.reloadConfig(msg.Config, msg.Toast))
+		// Groups and pins apply right away, whatever the current view
+		for _, sec := range []section.Section{m.repos, m.worflows} {
+			sec.UpdateContext(m.ctx)
+			_, cmd = sec.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case commands.RepositoriesReloadedMsg:
+		// The repositories view is updated whatever the current view
+		cmds = append(cmds, m.reloadRepositories(msg))
+		return m, tea.Batch(cmds...)
+
+	case commands.AccessibleRepositoriesMsg:
+		var names []string
+		for _, name := range msg.Names {
+			if github.FindRepository(m.repositories, name) == nil {
+				names = append(names, name)
+			}
+		}
+		m.prompt.SetSuggestions(names)
+
+	case commands.JumpToMsg:
+		cmds = append(cmds, m.jumpTo(msg))
 
 	case commands.SectionChangedMsg:
 		m.OnSelectedRowChanged()
 
+	case commands.DiscoveryErrorMsg:
+		log.Println("Error:", msg.Error)
+		m.ctx.Error = msg.Error
+		m.errorLog.Add("discover the repositories of the config", msg.Error)
+
 	case commands.ErrorMsg:
 		log.Println("Error:", msg.Error)
-		return m, nil
+		m.ctx.Error = msg.Error
+		m.ctx.Refreshing = false
+		m.errorLog.Add(msg.Request, msg.Error)
 
 	case tea.WindowSizeMsg:
 		m.onWindowSizeChanged(msg)
@@ -145,10 +416,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var footerCmd tea.Cmd
 	m.footer, footerCmd = m.footer.Update(msg)
 
+	var finderCmd tea.Cmd
+	m.finder, finderCmd = m.finder.Update(msg)
+
+	var promptCmd tea.Cmd
+	m.prompt, promptCmd = m.prompt.Update(msg)
+
+	var toastCmd tea.Cmd
+	m.toast, toastCmd = m.toast.Update(msg)
+
 	cmds = append(
 		cmds,
 		sectionCmd,
 		footerCmd,
+		finderCmd,
+		promptCmd,
+		toastCmd,
 	)
 
 	return m, tea.Batch(cmds...)
@@ -163,6 +446,33 @@ func (m Model) View() string {
 		m.GetCurrentSection().View(),
 		m.sidebar.View(),
 	)
+	if m.finder.IsActive() {
+		content = lipgloss.Place(
+			m.ctx.ScreenWidth,
+			lipgloss.Height(content),
+			lipgloss.Center,
+			lipgloss.Center,
+			m.finder.View(),
+		)
+	} else if m.prompt.IsActive() {
+		content = lipgloss.Place(
+			m.ctx.ScreenWidth,
+			lipgloss.Height(content),
+			lipgloss.Center,
+			lipgloss.Center,
+			m.prompt.View(),
+		)
+	} else if m.errorLog.IsActive() {
+		content = lipgloss.Place(
+			m.ctx.ScreenWidth,
+			lipgloss.Height(content),
+			lipgloss.Center,
+			lipgloss.Center,
+			m.errorLog.View(),
+		)
+	} else {
+		content = m.toast.Overlay(content)
+	}
 
 	s.WriteString(content)
 	s.WriteString("\n")
@@ -171,12 +481,222 @@ func (m Model) View() string {
 	return s.String()
 }
 
+// switchTheme applies the theme following the current one, wrapping around to the first one
+func (m *Model) switchTheme() tea.Cmd {
+	dir, err := config.ThemesDir()
+	if err != nil {
+		return func() tea.Msg { return commands.ErrorMsg{Error: err, Request: "switch theme"} }
+	}
+
+	current := m.themeName
+	if current == "" {
+		current = styles.DefaultThemeName
+	}
+	names := styles.ThemeNames(dir)
+	next := names[(slices.Index(names, current)+1)%len(names)]
+
+	theme, err := styles.LoadTheme(next, dir)
+	if err != nil {
+		return func() tea.Msg { return commands.ErrorMsg{Error: err, Request: "switch to theme " + next} }
+	}
+	m.themeName = next
+	m.applyTheme(theme)
+	return commands.Toast(commands.ToastInfo, "Theme: "+next)
+}
+
+// applyTheme rebuilds the styles and re-renders everything rendered ahead of time
+func (m *Model) applyTheme(theme *styles.Theme) {
+	s := styles.BuildStyles(*theme)
+	m.ctx.Theme = theme
+	m.ctx.Styles = &s
+	m.footer.Help.Styles = s.Help
+
+	for _, sec := range []section.Section{m.repos, m.worflows, m.run, m.step} {
+		sec.UpdateContext(m.ctx)
+		sec.SetRows(sec.BuildRows())
+	}
+	m.OnSelectedRowChanged()
+}
+
+// poll schedules the next fetch of the repositories when notifications are polled for
+func (m Model) poll() tea.Cmd {
+	interval, err := m.ctx.Config.Notifications.PollInterval()
+	if err != nil || interval == 0 {
+		return nil
+	}
+	return commands.Poll(interval, m.pollID)
+}
+
+// observe notifies the runs of the fetched repositories completed since they were last fetched
+func (m *Model) observe(repos ...*github.Repository) tea.Cmd {
+	var notifications []notify.Notification
+	for _, repo := range repos {
+		notifications = append(notifications, m.tracker.Observe(repo)...)
+	}
+	if m.toastNotifications {
+		return commands.NotificationToast(notifications)
+	}
+	return commands.Notify(m.notifier, notifications)
+}
+
+// toggleHidden fetches the repositories again with or without the filters of the config
+func (m *Model) toggleHidden() tea.Cmd {
+	if len(m.ctx.Config.Github.Filters) == 0 {
+		return commands.Toast(commands.ToastInfo, "No filters in the config, nothing is hidden")
+	}
+	if m.ctx.Client == nil || m.ctx.Offline {
+		return commands.Toast(commands.ToastWarning, "Hidden workflows can only be fetched while online")
+	}
+	m.ctx.ShowHidden = !m.ctx.ShowHidden
+	m.ctx.Refreshing = true
+	toast := "Showing hidden workflows and runs"
+	if !m.ctx.ShowHidden {
+		toast = "Hiding filtered workflows and runs"
+	}
+	return tea.Batch(
+		commands.RefreshRepositories(m.ctx.Client, m.ctx.FetchConfig()),
+		commands.Toast(commands.ToastInfo, toast),
+	)
+}
+
+// reloadConfig applies a config read again from the config file, toast confirming the edit
+// that changed it when it comes from the UI. Key bindings are only read on startup.
+func (m *Model) reloadConfig(cfg *config.Config, toast string) tea.Cmd {
+	previous := m.ctx.Config
+	if reflect.DeepEqual(previous, cfg) {
+		// The watcher reported the edit first
+		if toast != "" {
+			return commands.Toast(commands.ToastSuccess, toast)
+		}
+		return nil
+	}
+	m.ctx.Config = cfg
+
+	if size, set, err := cfg.Cache.MaxSizeBytes(); err == nil {
+		if !set {
+			size = cache.DefaultMaxSize
+		}
+		cache.SetMaxSize(size)
+	}
+
+	var cmds []tea.Cmd
+	if !reflect.DeepEqual(cfg.Notifications, previous.Notifications) {
+		m.notifier, m.toastNotifications = notify.NewWithoutTerminal(cfg.Notifications)
+		m.tracker.SetConfig(cfg.Notifications)
+		// Restart polling with the new interval
+		m.pollID++
+		cmds = append(cmds, m.poll())
+	}
+	if cfg.Theme != previous.Theme {
+		dir, err := config.ThemesDir()
+		if err == nil {
+			var theme *styles.Theme
+			theme, err = styles.LoadTheme(cfg.Theme, dir)
+			if err == nil {
+				m.themeName = cfg.Theme
+				m.applyTheme(theme)
+			}
+		}
+		if err != nil {
+			cmds = append(cmds, func() tea.Msg { return commands.ErrorMsg{Error: err, Request: "load theme " + cfg.Theme} })
+		}
+	}
+
+	filtersChanged := !m.ctx.ShowHidden && !reflect.DeepEqual(cfg.Github.Filters, previous.Github.Filters)
+	switch {
+	case m.ctx.Client != nil && filtersChanged:
+		// The repositories already listed were fetched with the previous filters, fetch them all again
+		m.ctx.Refreshing = true
+		cmds = append(cmds, commands.RefreshRepositories(m.ctx.Client, m.ctx.FetchConfig()), reloadToast(toast, nil, nil))
+	case m.ctx.Client != nil && !reflect.DeepEqual(cfg.Github, previous.Github):
+		var known []string
+		for _, repo := range m.repositories {
+			known = append(known, repo.FullName)
+		}
+		cmds = append(cmds, commands.ReloadRepositories(m.ctx.Client, m.ctx.FetchConfig(), known, toast))
+	default:
+		cmds = append(cmds, reloadToast(toast, nil, nil))
+	}
+	return tea.Batch(cmds...)
+}
+
+// reloadRepositories drops the repositories removed from the config and lists the added ones,
+// which are filled in as they are fetched
+func (m *Model) reloadRepositories(msg commands.RepositoriesReloadedMsg) tea.Cmd {
+	for _, name := range msg.Removed {
+		m.repositories = slices.DeleteFunc(m.repositories, func(repo *github.Repository) bool {
+			return strings.EqualFold(repo.FullName, name)
+		})
+	}
+	// The current repository stays open in the deeper views until the user returns
+	loading := commands.RepositoriesLoadingMsg{Names: msg.Added, Next: msg.Next}
+	for _, name := range msg.Added {
+		_, repoName, _ := strings.Cut(name, "/")
+		m.repositories = append(m.repositories, &github.Repository{Name: repoName, FullName: name})
+	}
+	loading.Repositories = m.repositories
+
+	m.repos.UpdateContext(m.ctx)
+	_, cmd := m.repos.Update(loading)
+	return tea.Batch(cmd, msg.Next, reloadToast(msg.Toast, msg.Added, msg.Removed))
+}
+
+// reloadToast confirms an edit made from the UI, or notifies that the config file changed
+func reloadToast(toast string, added, removed []string) tea.Cmd {
+	switch {
+	case toast != "":
+		return commands.Toast(commands.ToastSuccess, toast)
+	case len(added) > 0 || len(removed) > 0:
+		return commands.Toast(commands.ToastInfo,
+			fmt.Sprintf("Config reloaded: +%d −%d repositories", len(added), len(removed)))
+	}
+	return commands.Toast(commands.ToastInfo, "Config reloaded")
+}
+
+// openAddRepository prompts for a repository added to the config file, in the group listed if any
+func (m *Model) openAddRepository() tea.Cmd {
+	if !m.ctx.EditableConfig {
+		return commands.Toast(commands.ToastWarning, "Repositories can only be added to the config file")
+	}
+	if m.ctx.Client == nil {
+		return commands.Toast(commands.ToastWarning, "GitHub unreachable, try again later")
+	}
+
+	cfg := m.ctx.Config.Github
+	group := m.ctx.Group
+	if group == "" && len(cfg.Repositories) == 0 && len(cfg.Groups) > 0 {
+		// Ungrouped repositories would not be listed
+		group = cfg.GroupNames()[0]
+	}
+	title := "Add a repository"
+	if group != "" {
+		title += " to " + group
+	}
+
+	client, profile := m.ctx.Client, m.ctx.Profile
+	return tea.Batch(
+		m.prompt.Open(title, "owner/repo", func(name string) tea.Cmd {
+			return commands.AddRepository(client, profile, name, group)
+		}),
+		commands.FetchAccessibleRepositories(client),
+	)
+}
+
+// retryToast confirms that a failed item was fetched again, or reports the new failure
+func retryToast(name string, err error) tea.Cmd {
+	if err != nil {
+		return commands.Toast(commands.ToastError, "Failed to reload "+name+": "+github.ShortError(err))
+	}
+	return commands.Toast(commands.ToastSuccess, "Reloaded "+name)
+}
+
 func (m *Model) onWindowSizeChanged(msg tea.WindowSizeMsg) {
 	m.ctx.ScreenWidth = msg.Width
 	m.ctx.ScreenHeight = msg.Height
 	m.ctx.MainContentWidth = msg.Width - constants.SideBarWidth
 	m.ctx.MainContentHeight = msg.Height - constants.FooterHeight - constants.HeaderHeight
 	m.footer.SetWidth(msg.Width)
+	m.errorLog, _ = m.errorLog.Update(msg)
 }
 
 func (m *Model) updateCurrentSection(msg tea.Msg) (cmd tea.Cmd) {
@@ -197,6 +717,102 @@ func (m *Model) updateCurrentSection(msg tea.Msg) (cmd tea.Cmd) {
 	return cmd
 }
 
+// jumpTo switches to the deepest view targeted by msg, selecting the matching row in every section on the way
+func (m *Model) jumpTo(msg commands.JumpToMsg) tea.Cmd {
+	if msg.Repository == nil {
+		return nil
+	}
+	if m.ctx.View == context.LogStepView || m.ctx.View == context.LogView {
+		m.ctx.MainContentWidth -= constants.SideBarWidth
+	}
+
+	var cmds []tea.Cmd
+	m.ctx.View = context.RepoView
+	m.repos.SelectRow(func(row github.RowData) bool {
+		return row == msg.Repository
+	})
+
+	if msg.Run != nil {
+		cmds = append(cmds, m.openWorkflows(msg.Repository))
+		m.worflows.SelectRow(func(row github.RowData) bool {
+			return row == msg.Run
+		})
+	}
+
+	if msg.Run != nil && msg.Job != nil {
+		m.ctx.View = context.RunView
+		m.run.UpdateContext(m.ctx)
+		_, cmd := m.run.Update(commands.WorkflowRunMsg{RunWithJobs: msg.Run})
+		cmds = append(cmds, cmd)
+		m.run.SelectRow(func(row github.RowData) bool {
+			return row == msg.Job
+		})
+	}
+
+	m.OnSelectedRowChanged()
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) openWorkflows(repo *github.Repository) tea.Cmd {
+	m.ctx.View = context.WorkflowView
+	m.worflows.UpdateContext(m.ctx)
+	_, cmd := m.worflows.Update(commands.WorkflowsMsg{Workflows: repo})
+	return cmd
+}
+
+func (m *Model) openStartRepository() tea.Cmd {
+	for _, repo := range m.repositories {
+		if !strings.EqualFold(repo.FullName, m.startRepository) {
+			continue
+		}
+		if m.startRun != 0 {
+			return m.openStartRun(repo)
+		}
+		m.repos.SelectRow(func(row github.RowData) bool {
+			return row == repo
+		})
+		return m.openWorkflows(repo)
+	}
+	return nil
+}
+
+// openStartRun opens the run view of the start run, or the step view when a start job is set
+func (m *Model) openStartRun(repo *github.Repository) tea.Cmd {
+	var run *github.WorkflowRun
+	var job *github.Job
+	for _, workflow := range repo.Workflows {
+		for _, r := range workflow.Runs {
+			if r.ID != m.startRun {
+				continue
+			}
+			run = r
+			for _, j := range r.Jobs {
+				if j.ID == m.startJob {
+					job = j
+				}
+			}
+		}
+	}
+	if run == nil {
+		return nil
+	}
+
+	if job == nil {
+		// Open the run view even without a job to preselect
+		cmd := m.jumpTo(commands.JumpToMsg{Repository: repo, Run: run})
+		m.ctx.View = context.RunView
+		m.run.UpdateContext(m.ctx)
+		_, runCmd := m.run.Update(commands.WorkflowRunMsg{RunWithJobs: run})
+		m.OnSelectedRowChanged()
+		return tea.Batch(cmd, runCmd)
+	}
+
+	cmd := m.jumpTo(commands.JumpToMsg{Repository: repo, Run: run, Job: job})
+	m.ctx.View = context.LogStepView
+	m.ctx.MainContentWidth += constants.SideBarWidth
+	return tea.Batch(cmd, commands.GoToStep(job))
+}
+
 func (m *Model) GetCurrentSection() section.Section {
 	switch m.ctx.View {
 	case context.RepoView:
@@ -223,10 +839,15 @@ func (m *Model) OnSelectedRowChanged() {
 	case context.RepoView:
 		if repo, ok := currentRow.(*github.Repository); ok {
 			m.sidebar.GenerateRepoSidebarContent(repo)
+		} else {
+			m.sidebar.SetContent("")
 		}
 	case context.WorkflowView:
-		if workflowRun, ok := currentRow.(*github.WorkflowRun); ok {
-			m.sidebar.GenerateWorkflowSidebarContent(workflowRun)
+		switch row := currentRow.(type) {
+		case *github.WorkflowRun:
+			m.sidebar.GenerateWorkflowSidebarContent(row)
+		case *github.Workflow:
+			m.sidebar.GenerateWorkflowErrorSidebarContent(row)
 		}
 	case context.RunView:
 		if jobData, ok := currentRow.(*github.Job); ok {
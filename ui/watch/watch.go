@@ -0,0 +1,297 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cpaluszek/gh-ci/config"
+	"github.com/cpaluszek/gh-ci/github"
+	"github.com/cpaluszek/gh-ci/notify"
+	"github.com/cpaluszek/gh-ci/ui/context"
+	"github.com/cpaluszek/gh-ci/ui/keys"
+	"github.com/cpaluszek/gh-ci/ui/styles"
+	"github.com/cpaluszek/gh-ci/ui/utils"
+)
+
+const (
+	// maxFailures is the number of consecutive failed fetches after which watching stops
+	maxFailures = 5
+	// maxBackoff bounds the delay between fetches after failures
+	maxBackoff = time.Minute
+)
+
+type runsMsg struct {
+	runs []*github.WorkflowRun
+	err  error
+}
+
+type refreshMsg struct{}
+
+// Model renders a live tree of runs, jobs and steps until every run completes.
+// The notifier, if any, is told about each run that completes and matches the notification rules.
+type Model struct {
+	ctx         *context.Context
+	client      *github.Client
+	owner       string
+	repo        string
+	interval    time.Duration
+	runs        []*github.WorkflowRun
+	notifier    notify.Backend
+	tracker     *notify.Tracker
+	spinner     spinner.Model
+	err         error
+	failures    int   // consecutive failed fetches
+	lastErr     error // shown while retrying after a failed fetch
+	Interrupted bool
+	// defaultBranch of the repository, matched by the 'default' branch of the notification rules
+	defaultBranch string
+}
+
+func NewModel(client *github.Client, owner, repo string, runs []*github.WorkflowRun, interval time.Duration, theme *styles.Theme,
+	notifier notify.Backend, rules config.NotificationsConfig, defaultBranch string) Model {
+	s := styles.BuildStyles(*theme)
+
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+	sp.Style = s.InProgress
+
+	m := Model{
+		ctx: &context.Context{
+			Theme:  theme,
+			Styles: &s,
+		},
+		client:        client,
+		owner:         owner,
+		repo:          repo,
+		interval:      interval,
+		runs:          runs,
+		notifier:      notifier,
+		tracker:       notify.NewTracker(rules),
+		spinner:       sp,
+		defaultBranch: defaultBranch,
+	}
+	// Runs already completed when watching starts are not notified, those of unknown status,
+	// watched by URL, are recorded by the first fetch
+	var known []*github.WorkflowRun
+	for _, run := range runs {
+		if run.Status != "" {
+			known = append(known, run)
+		}
+	}
+	m.tracker.Observe(m.repository(known))
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.fetch())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.Keys.Quit) || msg.Type == tea.KeyEsc {
+			m.Interrupted = true
+			return m, tea.Quit
+		}
+
+	case runsMsg:
+		if msg.err != nil {
+			m.failures++
+			m.lastErr = msg.err
+			if m.failures >= maxFailures {
+				m.err = msg.err
+				return m, tea.Quit
+			}
+			return m, m.refresh(m.backoff())
+		}
+		m.failures = 0
+		m.lastErr = nil
+		notifyCmd := m.notifyCompleted(msg.runs)
+		m.runs = msg.runs
+		if m.Completed() {
+			// Notifications are delivered before quitting
+			return m, tea.Sequence(notifyCmd, tea.Quit)
+		}
+		return m, tea.Batch(notifyCmd, m.refresh(m.interval))
+
+	case refreshMsg:
+		return m, m.fetch()
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// refresh schedules the next fetch
+func (m Model) refresh(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return refreshMsg{}
+	})
+}
+
+// backoff doubles the interval with each consecutive failure
+func (m Model) backoff() time.Duration {
+	delay := m.interval << (m.failures - 1)
+	if delay <= 0 || delay > maxBackoff {
+		return max(maxBackoff, m.interval)
+	}
+	return delay
+}
+
+func (m Model) fetch() tea.Cmd {
+	ids := make([]int64, len(m.runs))
+	for i, run := range m.runs {
+		ids[i] = run.ID
+	}
+	return func() tea.Msg {
+		runs, err := m.client.FetchRunsWithJobs(m.owner, m.repo, ids)
+		return runsMsg{runs: runs, err: err}
+	}
+}
+
+// notifyCompleted notifies the runs that completed since the previous fetch and match the rules,
+// as the TUI does
+func (m Model) notifyCompleted(runs []*github.WorkflowRun) tea.Cmd {
+	if m.notifier == nil {
+		return nil
+	}
+	notifications := m.tracker.Observe(m.repository(runs))
+	if len(notifications) == 0 {
+		return nil
+	}
+	notifier := m.notifier
+	return func() tea.Msg {
+		// Logs are discarded while watching, a failed notification is not worth interrupting for
+		_ = notify.Send(notifier, notifications...)
+		return nil
+	}
+}
+
+// repository holds the runs in the shape observed by the tracker
+func (m Model) repository(runs []*github.WorkflowRun) *github.Repository {
+	return &github.Repository{
+		Name:          m.repo,
+		FullName:      m.owner + "/" + m.repo,
+		DefaultBranch: m.defaultBranch,
+		Workflows:     []*github.Workflow{{Runs: runs}},
+	}
+}
+
+// Completed reports whether every watched run has completed
+func (m Model) Completed() bool {
+	for _, run := range m.runs {
+		if run.Status != "completed" {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Model) Runs() []*github.WorkflowRun {
+	return m.runs
+}
+
+func (m Model) Err() error {
+	return m.err
+}
+
+func (m Model) View() string {
+	var lines []string
+	for _, run := range m.runs {
+		title := fmt.Sprintf("%s %s · %s",
+			m.ctx.Styles.Title.Render(run.Name),
+			run.DisplayTitle,
+			m.ctx.Styles.Skipped.Render(run.HeadBranch+" "+shortSha(run.HeadSha)))
+		lines = append(lines, m.statusSymbol(run.Status, run.Conclusion, true)+title)
+
+		for i, job := range run.Jobs {
+			branch, indent := "├ ", "│ "
+			if i == len(run.Jobs)-1 {
+				branch, indent = "└ ", "  "
+			}
+			duration := ""
+			if job.Status == "completed" {
+				duration = m.ctx.Styles.Skipped.Render(" " + utils.GetJobDuration(job))
+			}
+			lines = append(lines, "  "+branch+m.statusSymbol(job.Status, job.Conclusion, false)+job.Name+duration)
+
+			// Only expand the jobs worth looking at
+			if job.Status == "completed" && !github.IsFailureConclusion(job.Conclusion) {
+				continue
+			}
+			for j, step := range job.Steps {
+				stepBranch := "├ "
+				if j == len(job.Steps)-1 {
+					stepBranch = "└ "
+				}
+				lines = append(lines, "  "+indent+stepBranch+m.statusSymbol(step.Status, step.Conclusion, false)+step.Name)
+			}
+		}
+	}
+
+	if m.err != nil {
+		lines = append(lines, m.ctx.Styles.Error.Render("Error: "+m.err.Error()))
+	} else if m.lastErr != nil {
+		lines = append(lines, "", m.ctx.Styles.Error.Render(fmt.Sprintf(
+			"Error: %s, retrying in %s (%d/%d)", m.lastErr, m.backoff(), m.failures, maxFailures)))
+	} else if !m.Completed() && !m.Interrupted {
+		lines = append(lines, "", m.spinner.View()+" "+m.ctx.Styles.Skipped.Render(
+			fmt.Sprintf("Refreshing every %s, press q to stop watching", m.interval)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...) + "\n"
+}
+
+func (m Model) statusSymbol(status, conclusion string, isRun bool) string {
+	if status == "in_progress" {
+		return m.spinner.View() + " "
+	}
+	if isRun {
+		return utils.CleanANSIEscapes(utils.GetStatusSymbol(m.ctx, status, conclusion))
+	}
+	return utils.CleanANSIEscapes(utils.GetJobStatusSymbol(m.ctx, status, conclusion))
+}
+
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// FailedStepExcerpts returns the last lines of the logs of every failed step
+func FailedStepExcerpts(client *github.Client, owner, repo string, runs []*github.WorkflowRun, maxLines int) (string, error) {
+	var out strings.Builder
+	for _, run := range runs {
+		for _, job := range run.Jobs {
+			if !github.IsFailureConclusion(job.Conclusion) {
+				continue
+			}
+
+			steps, err := client.GetLogs(owner, repo, fmt.Sprint(run.ID), run.Attempt(), job.Name)
+			if err != nil {
+				return out.String(), err
+			}
+
+			for _, step := range steps {
+				if !github.IsFailureConclusion(step.Status) {
+					continue
+				}
+				fmt.Fprintf(&out, "\n%s / %s / %s\n", run.Name, job.Name, step.Title)
+				logs := step.Logs[max(len(step.Logs)-maxLines, 0):]
+				for _, entry := range logs {
+					fmt.Fprintln(&out, "  "+entry.Message)
+				}
+			}
+		}
+	}
+	return out.String(), nil
+}
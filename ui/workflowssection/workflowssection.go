@@ -1,10 +1,15 @@
 package workflowssection
 
 import (
+	"cmp"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cpaluszek/gh-ci/config"
 	"github.com/cpaluszek/gh-ci/github"
 	"github.com/cpaluszek/gh-ci/ui/commands"
 	"github.com/cpaluszek/gh-ci/ui/components/table"
@@ -15,15 +20,26 @@ import (
 	"github.com/cpaluszek/gh-ci/ui/utils"
 )
 
+// WorkflowRunInfo is a row of the table, Run is nil for a workflow whose runs failed to load
 type WorkflowRunInfo struct {
 	Workflow *github.Workflow
 	Run      *github.WorkflowRun
 }
 
+// row returns the run, or the workflow when its runs failed to load
+func (r WorkflowRunInfo) row() github.RowData {
+	if r.Run == nil {
+		return r.Workflow
+	}
+	return r.Run
+}
+
 type Model struct {
 	section.BaseModel
 	workflows *github.Repository
 	allRuns   []WorkflowRunInfo
+	// retrying holds the IDs of the failed workflows being fetched again
+	retrying map[int64]bool
 }
 
 func NewModel(ctx *context.Context) Model {
@@ -72,6 +88,7 @@ func NewModel(ctx *context.Context) Model {
 				Grow:  true,
 			},
 		},
+		table.Sort{Column: 4, Desc: true},
 	)
 
 	return Model{
@@ -90,22 +107,117 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 		m.Table.FirstItem()
 		cmds = append(cmds, commands.SectionChanged)
 
+	case commands.RepositoriesMsg:
+		// The displayed repository is updated in place when repositories are refreshed
+		if m.workflows != nil {
+			m.refreshRuns()
+			cmds = append(cmds, commands.SectionChanged)
+		}
+
+	case commands.RepositoryMsg:
+		if m.workflows != nil && msg.Repository == m.workflows {
+			m.refreshRuns()
+			cmds = append(cmds, commands.SectionChanged)
+		}
+
+	case commands.ConfigChangedMsg:
+		if msg.Config != nil && m.workflows != nil {
+			m.sortRows()
+			cmds = append(cmds, commands.SectionChanged)
+		}
+
+	case commands.WorkflowRefreshedMsg:
+		delete(m.retrying, msg.Workflow.ID)
+		if m.workflows != nil && msg.Repository == m.workflows {
+			m.refreshRuns()
+			cmds = append(cmds, commands.SectionChanged)
+		}
+
 	case tea.KeyMsg:
+		if m.UpdateSort(msg) {
+			m.sortRows()
+			return m, commands.SectionChanged
+		}
+
 		switch {
+		case key.Matches(msg, keys.Keys.Branch):
+			if m.Ctx.Branch == "" || m.workflows == nil {
+				return m, nil
+			}
+			m.Ctx.FilterByBranch = !m.Ctx.FilterByBranch
+			m.allRuns = m.buildRunsList()
+			m.Table.SetRows(m.BuildRows())
+			m.Table.FirstItem()
+			return m, commands.SectionChanged
+
 		case key.Matches(msg, keys.Keys.OpenGitHub):
-			if m.workflows == nil || len(m.allRuns) == 0 {
+			row := m.GetCurrentRow()
+			if row == nil || row.GetURL() == "" {
+				return m, nil
+			}
+
+			return m, commands.OpenBrowser(row.GetURL())
+
+		case key.Matches(msg, keys.Keys.Pin):
+			workflow := m.currentWorkflow()
+			if workflow == nil {
 				return m, nil
 			}
-			currentIndex := m.Table.GetCurrItem()
-			if currentIndex < 0 || currentIndex >= len(m.allRuns) {
+			pin := config.WorkflowPin(m.workflows.FullName, workflow.Name)
+			pinned := slices.Clone(m.Ctx.Config.Github.PinnedWorkflows)
+			done := "Unpinned " + workflow.Name
+			if i := m.Ctx.Config.Github.WorkflowPinIndex(m.workflows.FullName, workflow.Name); i >= 0 {
+				pinned = slices.Delete(pinned, i, i+1)
+			} else {
+				pinned = append(pinned, pin)
+				done = "Pinned " + workflow.Name
+			}
+			return m, m.savePinned(pinned, done)
+
+		case key.Matches(msg, keys.Keys.MoveUp), key.Matches(msg, keys.Keys.MoveDown):
+			workflow := m.currentWorkflow()
+			if workflow == nil {
 				return m, nil
 			}
-			url := m.allRuns[currentIndex].Run.URL
-			if url == "" {
+			// Only the pins of this repository are visible, moving swaps with the neighbouring one
+			var indexes []int
+			current := -1
+			for i, pin := range m.Ctx.Config.Github.PinnedWorkflows {
+				repo, name, _ := strings.Cut(pin, ":")
+				if !strings.EqualFold(repo, m.workflows.FullName) {
+					continue
+				}
+				if name == workflow.Name {
+					current = len(indexes)
+				}
+				indexes = append(indexes, i)
+			}
+			if current < 0 {
+				return m, commands.Toast(commands.ToastInfo, "Only pinned workflows can be moved")
+			}
+			next, done := current+1, "Moved "+workflow.Name+" down"
+			if key.Matches(msg, keys.Keys.MoveUp) {
+				next, done = current-1, "Moved "+workflow.Name+" up"
+			}
+			if next < 0 || next >= len(indexes) {
 				return m, nil
 			}
+			pinned := slices.Clone(m.Ctx.Config.Github.PinnedWorkflows)
+			i, j := indexes[current], indexes[next]
+			pinned[i], pinned[j] = pinned[j], pinned[i]
+			return m, m.savePinned(pinned, done)
 
-			return m, commands.OpenBrowser(url)
+		case key.Matches(msg, keys.Keys.Retry):
+			workflow, ok := m.GetCurrentRow().(*github.Workflow)
+			if !ok || m.Ctx.Client == nil || m.retrying[workflow.ID] {
+				return m, nil
+			}
+			if m.retrying == nil {
+				m.retrying = make(map[int64]bool)
+			}
+			m.retrying[workflow.ID] = true
+			m.Table.SetRows(m.BuildRows())
+			return m, commands.RetryWorkflow(m.Ctx.Client, m.Ctx.FetchConfig(), m.workflows, workflow)
 		}
 	}
 
@@ -121,10 +233,17 @@ func (m *Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 func (m *Model) buildRunsList() []WorkflowRunInfo {
 	var runs []WorkflowRunInfo
 	for _, workflow := range m.workflows.Workflows {
+		if workflow.Error != nil && len(workflow.Runs) == 0 {
+			runs = append(runs, WorkflowRunInfo{Workflow: workflow})
+			continue
+		}
 		if len(workflow.Runs) == 0 {
 			continue
 		}
 		for _, runWithJob := range workflow.Runs {
+			if m.Ctx.FilterByBranch && runWithJob.HeadBranch != m.Ctx.Branch {
+				continue
+			}
 			runs = append(runs, WorkflowRunInfo{
 				Workflow: workflow,
 				Run:      runWithJob,
@@ -132,19 +251,126 @@ func (m *Model) buildRunsList() []WorkflowRunInfo {
 		}
 	}
 
-	// Sort by creation time (most recent first)
-	sort.Slice(runs, func(i, j int) bool {
+	// Keep the most recent runs first when the table is not sorted, failed workflows last
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].Run == nil || runs[j].Run == nil {
+			return runs[j].Run == nil && runs[i].Run != nil
+		}
 		return runs[i].Run.CreatedAt.After(runs[j].Run.CreatedAt)
 	})
+	table.SortItems(&m.Table, runs, sortKey)
+	m.sortPinned(runs)
 
 	return runs
 }
 
+// sortPinned moves the runs of pinned workflows first, in the order of the config
+func (m *Model) sortPinned(runs []WorkflowRunInfo) {
+	cfg := m.Ctx.Config.Github
+	slices.SortStableFunc(runs, func(a, b WorkflowRunInfo) int {
+		return cmp.Compare(m.pinRank(cfg, a.Workflow), m.pinRank(cfg, b.Workflow))
+	})
+}
+
+func (m *Model) pinRank(cfg config.GithubConfig, workflow *github.Workflow) int {
+	if i := cfg.WorkflowPinIndex(m.workflows.FullName, workflow.Name); i >= 0 {
+		return i
+	}
+	return len(cfg.PinnedWorkflows)
+}
+
+// currentWorkflow returns the workflow of the selected row
+func (m *Model) currentWorkflow() *github.Workflow {
+	i := m.Table.GetCurrItem()
+	if m.workflows == nil || i < 0 || i >= len(m.allRuns) {
+		return nil
+	}
+	return m.allRuns[i].Workflow
+}
+
+// savePinned writes the pinned workflows to the config file, the rows are ordered again once it is reloaded
+func (m *Model) savePinned(pinned []string, done string) tea.Cmd {
+	if !m.Ctx.EditableConfig {
+		return commands.Toast(commands.ToastWarning, "Workflows can only be pinned in the config file")
+	}
+	return commands.EditConfig(m.Ctx.Profile, "save the pinned workflows", func() (string, error) {
+		return done, config.SetPinnedWorkflows(pinned)
+	})
+}
+
+// refreshRuns rebuilds the runs of the repository, keeping the selected run
+func (m *Model) refreshRuns() {
+	var selectedID int64
+	if selected, ok := m.GetCurrentRow().(*github.WorkflowRun); ok {
+		selectedID = selected.ID
+	}
+	m.allRuns = m.buildRunsList()
+	m.Table.SetRows(m.BuildRows())
+	m.SelectRow(func(row github.RowData) bool {
+		run, ok := row.(*github.WorkflowRun)
+		return ok && run.ID == selectedID
+	})
+}
+
+// sortRows orders the runs by the table sort and rebuilds the rows, keeping the selection
+func (m *Model) sortRows() {
+	selected := m.GetCurrentRow()
+	table.SortItems(&m.Table, m.allRuns, sortKey)
+	m.sortPinned(m.allRuns)
+	m.Table.SetRows(m.BuildRows())
+	if selected != nil {
+		m.SelectRow(func(row github.RowData) bool {
+			return row == selected
+		})
+	}
+}
+
+func sortKey(runInfo WorkflowRunInfo, column int) table.SortKey {
+	run := runInfo.Run
+	if run == nil {
+		run = &github.WorkflowRun{}
+	}
+	switch column {
+	case 1:
+		return runInfo.Workflow.Name
+	case 2:
+		if run.Status == "completed" {
+			return run.Conclusion
+		}
+		return run.Status
+	case 3:
+		return run.HeadBranch
+	case 4:
+		return run.CreatedAt
+	case 5:
+		if run.UpdatedAt.After(run.CreatedAt) {
+			return run.UpdatedAt.Sub(run.CreatedAt)
+		}
+		return time.Since(run.CreatedAt)
+	case 6:
+		// Order by number of failed jobs
+		failed := 0
+		for _, job := range run.Jobs {
+			if job.Conclusion == "failure" {
+				failed++
+			}
+		}
+		return failed
+	case 7:
+		return run.HeadCommit.Message
+	}
+	return nil
+}
+
 func (m Model) BuildRows() []table.Row {
 	var rows []table.Row
 	for _, runInfo := range m.allRuns {
 		run := runInfo.Run
 		workflow := runInfo.Workflow
+		if run == nil {
+			rows = append(rows, m.buildErrorRow(workflow))
+			continue
+		}
 
 		duration := utils.GetWorkflowRunDuration(run)
 		commitMsg := run.HeadCommit.Message
@@ -157,10 +383,15 @@ func (m Model) BuildRows() []table.Row {
 		}
 		jobs = utils.CleanANSIEscapes(jobs)
 
+		name := workflow.Name
+		if m.Ctx.Config.Github.WorkflowPinIndex(m.workflows.FullName, workflow.Name) >= 0 {
+			name = m.Ctx.Theme.Symbols.Pinned + name
+		}
+
 		// Table row
 		rows = append(rows, table.Row{
 			" " + utils.GetRunEventSymbol(m.Ctx, run.Event),
-			workflow.Name,
+			name,
 			displayStatus,
 			run.HeadBranch,
 			utils.FormatTime(run.CreatedAt),
@@ -172,6 +403,25 @@ func (m Model) BuildRows() []table.Row {
 	return rows
 }
 
+// buildErrorRow shows a workflow whose runs failed to load
+func (m Model) buildErrorRow(workflow *github.Workflow) table.Row {
+	status := utils.GetStatusSymbol(m.Ctx, "completed", "failure") + github.ShortError(workflow.Error)
+	if m.retrying[workflow.ID] {
+		status = utils.GetStatusSymbol(m.Ctx, "in_progress", "") + "retrying"
+	}
+	message, _, _ := strings.Cut(workflow.Error.Error(), "\n")
+	return table.Row{
+		"",
+		workflow.Name,
+		utils.CleanANSIEscapes(status),
+		"",
+		"",
+		"",
+		"",
+		message,
+	}
+}
+
 func (m *Model) GetDimensions() constants.Dimensions {
 	return constants.Dimensions{
 		Width:  m.Ctx.MainContentWidth,
@@ -203,6 +453,16 @@ func (m *Model) Fetch() []tea.Cmd {
 	return nil
 }
 
+func (m *Model) SelectRow(match func(row github.RowData) bool) bool {
+	for i, runInfo := range m.allRuns {
+		if match(runInfo.row()) {
+			m.Table.SetCurrItem(i)
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) GetCurrentRow() github.RowData {
 	if m.workflows == nil || len(m.allRuns) == 0 {
 		return nil
@@ -213,5 +473,5 @@ func (m *Model) GetCurrentRow() github.RowData {
 		return nil
 	}
 
-	return m.allRuns[currentIndex].Run
+	return m.allRuns[currentIndex].row()
 }